@@ -1,12 +1,19 @@
 package ts_test
 
 import (
+	"log/slog"
 	"testing"
 	"time"
 
 	ts "github.com/BrownNPC/thing-system"
 )
 
+// Compare the build modes with:
+//
+//	go test -bench . -tags ts_release
+//	go test -bench .
+//	go test -bench . -tags ts_debug
+
 func BenchmarkLoop10kThings(b *testing.B) {
 	const thingCount = 10_000
 	things := ts.NewThings(thingCount, Thing{})
@@ -47,7 +54,34 @@ func BenchmarkGet(b *testing.B) {
 	things := ts.NewThings(1, Thing{})
 	Plr := things.New(Thing{Kind: KindPlayer})
 
+	b.Attr("mode", ts.BuildMode)
 	for b.Loop() {
 		_ = things.Get(Plr)
 	}
 }
+
+// BenchmarkGetNilRef measures the warning of Get on a NilRef, with a logger that discards it.
+func BenchmarkGetNilRef(b *testing.B) {
+	ts.SetLogger(slog.New(slog.DiscardHandler))
+	defer ts.SetLogger(nil)
+	things := ts.NewThings(1, Thing{})
+
+	b.Attr("mode", ts.BuildMode)
+	b.ReportAllocs()
+	for b.Loop() {
+		things.Get(ts.ThingRef{}).Health = 1
+	}
+}
+
+func BenchmarkAppendDelete(b *testing.B) {
+	things := ts.NewThings(16, Thing{})
+	plr := things.New(Thing{Kind: KindPlayer})
+	things.Get(plr).Inventory.Init(plr, things)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		item := things.New(Thing{Kind: KindItem})
+		things.Get(plr).Inventory.Append(item)
+		things.Delete(item)
+	}
+}
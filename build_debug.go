@@ -0,0 +1,14 @@
+//go:build ts_debug
+
+package ts
+
+// BuildMode is "debug", "release" or "default", depending on the build tags.
+const BuildMode = "debug"
+
+// debugMode is true when building with -tags ts_debug.
+// Freed slots are poisoned, Get remembers where it was called from,
+// and EndFrame validates the pool.
+const debugMode = true
+
+// releaseMode is true when building with -tags ts_release.
+const releaseMode = false
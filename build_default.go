@@ -0,0 +1,12 @@
+//go:build !ts_debug && !ts_release
+
+package ts
+
+// BuildMode is "debug", "release" or "default", depending on the build tags.
+const BuildMode = "default"
+
+// debugMode is true when building with -tags ts_debug.
+const debugMode = false
+
+// releaseMode is true when building with -tags ts_release.
+const releaseMode = false
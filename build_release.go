@@ -0,0 +1,14 @@
+//go:build ts_release && !ts_debug
+
+package ts
+
+// BuildMode is "debug", "release" or "default", depending on the build tags.
+const BuildMode = "release"
+
+// debugMode is true when building with -tags ts_debug.
+const debugMode = false
+
+// releaseMode is true when building with -tags ts_release.
+// Nothing is logged, so the warnings and the lookups of their callers compile away.
+// Refs are still checked, a NilRef still returns the Nil Thing.
+const releaseMode = true
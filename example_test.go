@@ -82,7 +82,7 @@ func Example() {
 		// Delete:
 		// invalidates the Ref, so this Thing can be reused later.
 		// Auto removes ref from Lists (like Plr.Inventory)
-		// NOTE: Deleting while looping is unsafe.
+		// NOTE: Deleting the current Thing while looping is also safe.
 		defer things.Delete(ref) // defer to delete after the loop
 
 		fmt.Printf("Queued %v for deletion. That means it's Active:%v as of now\n", ref.String(), things.IsNotNil(ref)) // IsActive=true
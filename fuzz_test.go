@@ -0,0 +1,24 @@
+package ts_test
+
+import (
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+	"github.com/BrownNPC/thing-system/tstest"
+)
+
+func fuzzInventory(f *testing.F, policy ts.OwnerPolicy) {
+	for _, seed := range tstest.Seeds() {
+		f.Add(seed)
+	}
+	h := tstest.Harness[Thing]{
+		Capacity: 8,
+		List:     func(t *Thing) *ts.List[Thing] { return &t.Inventory },
+		Policy:   policy,
+	}
+	f.Fuzz(h.Fuzz)
+}
+
+func FuzzInventory(f *testing.F)        { fuzzInventory(f, ts.OrphanMembers) }
+func FuzzInventoryCascade(f *testing.F) { fuzzInventory(f, ts.CascadeDelete) }
+func FuzzInventoryForbid(f *testing.F)  { fuzzInventory(f, ts.Forbid) }
@@ -0,0 +1,332 @@
+// Package inspect serves web pages that show the live Things of a pool,
+// to look inside a running server without attaching a debugger.
+//
+// The pool is only read from its own goroutine, when Poll is called:
+//
+//	inspector := inspect.NewHandler(things)
+//	go inspector.ListenAndServe("localhost:6061")
+//	for {
+//		update()
+//		things.EndFrame()
+//		inspector.Poll()
+//	}
+//
+// The pages are only served to localhost.
+package inspect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+const (
+	// maxRows is the number of Things listed in the table.
+	maxRows = 500
+	// maxElements is the number of elements shown of arrays, slices and Lists.
+	maxElements = 16
+)
+
+// ErrNotLocalhost is returned by ListenAndServe for addresses that can be reached from other machines.
+var ErrNotLocalhost = errors.New("inspect: address is not localhost")
+
+// Handler is an http.Handler that shows the Things of a pool.
+//
+//	/            a table of the live Things
+//	/thing/3.1   a single Thing, by the index and generation of its ref
+//
+// The Things are read with Peek, so showing them does not make EnableRefNulling scan them again.
+type Handler[Thing any] struct {
+	things   *ts.Things[Thing]
+	requests chan *request
+}
+
+// request is a page that is rendered by Poll.
+type request struct {
+	path string
+	out  bytes.Buffer
+	code int
+	done chan struct{}
+}
+
+// NewHandler returns a Handler that shows the Things of the pool.
+func NewHandler[Thing any](things *ts.Things[Thing]) *Handler[Thing] {
+	return &Handler[Thing]{things: things, requests: make(chan *request)}
+}
+
+// Poll renders the pages that were requested since the last Poll.
+// Call it from the goroutine that uses the pool, like after EndFrame.
+// Requests wait until Poll is called.
+func (handler *Handler[Thing]) Poll() {
+	for {
+		select {
+		case req := <-handler.requests:
+			req.code = handler.render(&req.out, req.path)
+			close(req.done)
+		default:
+			return
+		}
+	}
+}
+
+// ListenAndServe serves the Handler on addr, which must be on localhost like "localhost:6061".
+func (handler *Handler[Thing]) ListenAndServe(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if !isLocalhost(host) {
+		return fmt.Errorf("%w: %q", ErrNotLocalhost, addr)
+	}
+	return http.ListenAndServe(addr, handler)
+}
+
+// ServeHTTP waits for the next Poll to render the page.
+// Requests from other machines are refused, even if the Handler is mounted on a public server.
+func (handler *Handler[Thing]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil || !isLocalhost(remote) || !isLocalhost(hostname(r.Host)) {
+		http.Error(w, "the inspector is only served to localhost", http.StatusForbidden)
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req := &request{path: r.URL.Path, done: make(chan struct{})}
+	select {
+	case handler.requests <- req:
+	case <-r.Context().Done():
+		return
+	}
+	<-req.done
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(req.code)
+	w.Write(req.out.Bytes())
+}
+
+func hostname(hostport string) string {
+	if host, _, err := net.SplitHostPort(hostport); err == nil {
+		return host
+	}
+	return hostport
+}
+
+func isLocalhost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// render writes the page at path, and returns the status code.
+// Links are relative, so the Handler can be mounted under a prefix with http.StripPrefix.
+func (handler *Handler[Thing]) render(out *bytes.Buffer, path string) int {
+	path = strings.TrimPrefix(path, "/")
+	switch {
+	case path == "":
+		handler.renderTable(out)
+		return http.StatusOK
+	case strings.HasPrefix(path, "thing/"):
+		id := strings.TrimPrefix(path, "thing/")
+		if ref, err := ts.ParseThingRef(id); err == nil && handler.things.IsNotNil(ref) {
+			thing, _ := handler.things.Peek(ref)
+			handler.renderThing(out, ref, thing)
+			return http.StatusOK
+		}
+		page(out, "Not found", "../")
+		fmt.Fprintf(out, "<p>There is no live Thing %s.</p>\n", html.EscapeString(id))
+		return http.StatusNotFound
+	}
+	page(out, "Not found", "")
+	return http.StatusNotFound
+}
+
+// page writes the start of a page, with a link to the table unless table is empty.
+func page(out *bytes.Buffer, title, table string) {
+	fmt.Fprintf(out, "<!DOCTYPE html>\n<title>%s</title>\n<style>table{border-collapse:collapse}td,th{border:1px solid #ccc;padding:2px 6px;text-align:left;vertical-align:top}</style>\n", html.EscapeString(title))
+	if table != "" {
+		fmt.Fprintf(out, "<p><a href=\"%s\">All Things</a></p>\n", table)
+	}
+	fmt.Fprintf(out, "<h1>%s</h1>\n", html.EscapeString(title))
+}
+
+func (handler *Handler[Thing]) renderTable(out *bytes.Buffer) {
+	thingType := reflect.TypeFor[Thing]()
+	page(out, "Things", "")
+	out.WriteString("<table>\n<tr><th>Ref</th>")
+	columns := exportedFields(thingType)
+	if columns == nil {
+		out.WriteString("<th>Value</th>")
+	}
+	for _, field := range columns {
+		fmt.Fprintf(out, "<th>%s</th>", html.EscapeString(thingType.Field(field).Name))
+	}
+	out.WriteString("</tr>\n")
+
+	live := 0
+	for ref, thing := range handler.things.PeekEach() {
+		live++
+		if live > maxRows {
+			continue
+		}
+		fmt.Fprintf(out, "<tr><td><a href=\"thing/%s\">%s</a></td>", refID(ref), ref)
+		value := reflect.ValueOf(thing).Elem()
+		if columns == nil {
+			out.WriteString("<td>")
+			handler.value(out, value, ref, "thing/")
+			out.WriteString("</td>")
+		}
+		for _, field := range columns {
+			out.WriteString("<td>")
+			handler.value(out, value.Field(field), ref, "thing/")
+			out.WriteString("</td>")
+		}
+		out.WriteString("</tr>\n")
+	}
+	out.WriteString("</table>\n")
+	if live > maxRows {
+		fmt.Fprintf(out, "<p>Showing %d of %d live Things.</p>\n", maxRows, live)
+	} else {
+		fmt.Fprintf(out, "<p>%d live Things.</p>\n", live)
+	}
+}
+
+func (handler *Handler[Thing]) renderThing(out *bytes.Buffer, ref ts.ThingRef, thing *Thing) {
+	page(out, ref.String(), "../")
+	value := reflect.ValueOf(thing).Elem()
+	columns := exportedFields(value.Type())
+	if columns == nil {
+		out.WriteString("<p>")
+		handler.value(out, value, ref, "")
+		out.WriteString("</p>\n")
+		return
+	}
+	out.WriteString("<table>\n")
+	for _, field := range columns {
+		fmt.Fprintf(out, "<tr><th>%s</th><td>", html.EscapeString(value.Type().Field(field).Name))
+		handler.value(out, value.Field(field), ref, "")
+		out.WriteString("</td></tr>\n")
+	}
+	out.WriteString("</table>\n")
+}
+
+// exportedFields returns the indexes of the exported fields of t, or nil if t is not a struct.
+func exportedFields(t reflect.Type) []int {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := []int{}
+	for i := range t.NumField() {
+		if t.Field(i).IsExported() {
+			fields = append(fields, i)
+		}
+	}
+	return fields
+}
+
+// value writes v, which is inside of the Thing self.
+// ThingRefs and the members of Lists link to prefix followed by their id.
+func (handler *Handler[Thing]) value(out *bytes.Buffer, v reflect.Value, self ts.ThingRef, prefix string) {
+	if !v.CanInterface() {
+		return
+	}
+	switch v.Type() {
+	case reflect.TypeFor[ts.ThingRef]():
+		handler.ref(out, v.Interface().(ts.ThingRef), prefix)
+		return
+	case reflect.TypeFor[ts.List[Thing]]():
+		if v.CanAddr() {
+			handler.list(out, v.Addr().Interface().(*ts.List[Thing]), self, prefix)
+		}
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		out.WriteString("{")
+		for i, field := range exportedFields(v.Type()) {
+			if i > 0 {
+				out.WriteString(", ")
+			}
+			fmt.Fprintf(out, "%s: ", html.EscapeString(v.Type().Field(field).Name))
+			handler.value(out, v.Field(field), self, prefix)
+		}
+		out.WriteString("}")
+	case reflect.Array, reflect.Slice:
+		out.WriteString("[")
+		for i := range min(v.Len(), maxElements) {
+			if i > 0 {
+				out.WriteString(" ")
+			}
+			handler.value(out, v.Index(i), self, prefix)
+		}
+		if v.Len() > maxElements {
+			fmt.Fprintf(out, " … %d in total", v.Len())
+		}
+		out.WriteString("]")
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		if v.IsNil() {
+			out.WriteString("nil")
+		} else {
+			fmt.Fprintf(out, "(%s)", html.EscapeString(v.Type().String()))
+		}
+	default:
+		out.WriteString(html.EscapeString(fmt.Sprint(v.Interface())))
+	}
+}
+
+// ref writes a link to the Thing, or says why there is none.
+func (handler *Handler[Thing]) ref(out *bytes.Buffer, ref ts.ThingRef, prefix string) {
+	switch {
+	case ref == ts.ThingRef{}:
+		out.WriteString("NilRef")
+	case !handler.things.IsNotNil(ref):
+		fmt.Fprintf(out, "%s (deleted)", ref)
+	default:
+		fmt.Fprintf(out, "<a href=\"%s%s\">%s</a>", prefix, refID(ref), ref)
+	}
+}
+
+// list writes the members of the List if self owns it, or its owner otherwise.
+func (handler *Handler[Thing]) list(out *bytes.Buffer, list *ts.List[Thing], self ts.ThingRef, prefix string) {
+	if !list.IsLinked() {
+		out.WriteString("List (not linked)")
+		return
+	}
+	owner := list.Owner()
+	if owner != self {
+		out.WriteString("in List of ")
+		handler.ref(out, owner, prefix)
+		return
+	}
+	fmt.Fprintf(out, "List of %d: [", list.Len())
+	i := 0
+	for member := range list.PeekEach() {
+		if i == maxElements {
+			out.WriteString(" …")
+			break
+		}
+		if i > 0 {
+			out.WriteString(" ")
+		}
+		handler.ref(out, member, prefix)
+		i++
+	}
+	out.WriteString("]")
+}
+
+// refID returns the index and generation of the ref, like "3.1", which is used in links.
+func refID(ref ts.ThingRef) string {
+	return strings.TrimSuffix(strings.TrimPrefix(ref.String(), "Thing("), ")")
+}
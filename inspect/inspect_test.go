@@ -0,0 +1,154 @@
+package inspect_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ts "github.com/BrownNPC/thing-system"
+	"github.com/BrownNPC/thing-system/inspect"
+)
+
+func init() {
+	ts.SetLogger(nil)
+}
+
+type unit struct {
+	Name   string
+	Health int
+	Target ts.ThingRef
+	Stats  struct{ Speed float32 }
+	Items  ts.List[unit]
+	secret int
+}
+
+// serve starts the inspector, and polls it like a game loop until the test ends.
+func serve[Thing any](t *testing.T, things *ts.Things[Thing]) *httptest.Server {
+	t.Helper()
+	inspector := inspect.NewHandler(things)
+	server := httptest.NewServer(inspector)
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(time.Millisecond):
+				inspector.Poll()
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		close(stop)
+		<-stopped
+		server.Close()
+	})
+	return server
+}
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+// id returns the part of the URL of a Thing after "thing/".
+func id(ref ts.ThingRef) string {
+	return strings.TrimSuffix(strings.TrimPrefix(ref.String(), "Thing("), ")")
+}
+
+// link is how a ThingRef is shown on the page of a Thing.
+func link(ref ts.ThingRef) string {
+	return `<a href="` + id(ref) + `">` + ref.String() + `</a>`
+}
+
+func TestInspect(t *testing.T) {
+	things := ts.NewThings[unit](8)
+	player := things.New(unit{Name: "<player>", Health: 10, secret: 42})
+	sword := things.New(unit{Name: "sword"})
+	goblin := things.New(unit{Name: "goblin", Target: player})
+	things.Get(player).Items.Init(player, things)
+	things.Get(player).Items.Append(sword)
+	server := serve(t, things)
+
+	code, body := get(t, server.URL+"/")
+	if code != http.StatusOK {
+		t.Fatalf("expected status 200, got %v", code)
+	}
+	for _, want := range []string{
+		"<th>Name</th><th>Health</th><th>Target</th><th>Stats</th><th>Items</th>",
+		"&lt;player&gt;",
+		`<a href="thing/` + id(player) + `">` + player.String() + `</a>`,
+		"{Speed: 0}",
+		"3 live Things",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in table\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "secret") || strings.Contains(body, "42") {
+		t.Errorf("expected unexported fields to be hidden\n%s", body)
+	}
+
+	// a ThingRef field links to the Thing.
+	_, body = get(t, server.URL+"/thing/"+id(goblin))
+	if !strings.Contains(body, "<tr><th>Target</th><td>"+link(player)+"</td></tr>") {
+		t.Errorf("expected Target to link to the player\n%s", body)
+	}
+	// the owner links to its members, and the members to the owner.
+	_, body = get(t, server.URL+"/thing/"+id(player))
+	if !strings.Contains(body, "List of 1: ["+link(sword)+"]") {
+		t.Errorf("expected Items to link to the sword\n%s", body)
+	}
+	_, body = get(t, server.URL+"/thing/"+id(sword))
+	if !strings.Contains(body, "in List of "+link(player)) {
+		t.Errorf("expected sword to link to its owner\n%s", body)
+	}
+
+	things.Delete(sword)
+	for _, path := range []string{id(sword), "sword", "1.x", "99.0", "0.0"} {
+		if code, _ := get(t, server.URL+"/thing/"+path); code != http.StatusNotFound {
+			t.Errorf("expected %q to be not found, got status %v", path, code)
+		}
+	}
+}
+
+func TestInspectNotStruct(t *testing.T) {
+	things := ts.NewThings[int](4)
+	things.New(7)
+	_, body := get(t, serve(t, things).URL)
+	if !strings.Contains(body, "<th>Value</th>") || !strings.Contains(body, "<td>7</td>") {
+		t.Fatalf("expected Things that are not structs to be shown as values\n%s", body)
+	}
+}
+
+func TestInspectOnlyLocalhost(t *testing.T) {
+	inspector := inspect.NewHandler(ts.NewThings[unit](4))
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.RemoteAddr = "203.0.113.7:4000"
+	inspector.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected request from another machine to be forbidden, got status %v", recorder.Code)
+	}
+
+	for _, addr := range []string{":6061", "0.0.0.0:6061", "example.com:6061"} {
+		if err := inspector.ListenAndServe(addr); !errors.Is(err, inspect.ErrNotLocalhost) {
+			t.Errorf("expected ErrNotLocalhost for %q, got %v", addr, err)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package ts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// LevelError is returned by LoadLevel. It points at the line of the level file that caused it.
+type LevelError struct {
+	Line  int
+	Field string // like "door1.Target", empty if the error is not about a field.
+	Err   error
+}
+
+func (err *LevelError) Error() string {
+	if err.Field == "" {
+		return fmt.Sprintf("ts: level:%d: %v", err.Line, err.Err)
+	}
+	return fmt.Sprintf("ts: level:%d: %s: %v", err.Line, err.Field, err.Err)
+}
+
+func (err *LevelError) Unwrap() error {
+	return err.Err
+}
+
+// LoadLevel creates the Things of a level file in the pool, and returns their ThingRefs by name.
+//
+// A level is a JSON array of Things. Every Thing has:
+//   - "name": used to refer to it from other Things. Optional, names must be unique.
+//   - "thing": the fields of the Thing, decoded with encoding/json. Optional.
+//   - "refs": ThingRef fields of the Thing, set to the Thing with the name. null is NilRef.
+//   - "lists": List fields of the Thing. They are initialized, and the named Things are appended in order.
+//
+// Fields of nested structs in "refs" and "lists" are written with dots, like "Combat.LastHitBy".
+//
+//	[
+//		{"name": "switch3", "thing": {"Kind": 3}},
+//		{"name": "door1", "thing": {"Kind": 4}, "refs": {"Target": "switch3"}},
+//		{"name": "sword", "thing": {"Kind": 2}},
+//		{"name": "potion", "thing": {"Kind": 2}},
+//		{"name": "player", "thing": {"Kind": 1}, "lists": {"Inventory": ["sword", "potion"]}}
+//	]
+//
+// Errors are a *LevelError with the line, for unknown names, fields and type mismatches.
+// Nothing is left in the pool if there is an error.
+func LoadLevel[Thing any](things *Things[Thing], r io.Reader) (names map[string]ThingRef, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("ts: loading level: %w", err)
+	}
+	level := levelLoader[Thing]{things: things, data: data, names: make(map[string]ThingRef)}
+	defer func() {
+		if err != nil {
+			things.Delete(level.created...)
+		}
+	}()
+	if err := level.parse(); err != nil {
+		return nil, err
+	}
+	if err := level.create(); err != nil {
+		return nil, err
+	}
+	if err := level.link(); err != nil {
+		return nil, err
+	}
+	return level.names, nil
+}
+
+type levelLoader[Thing any] struct {
+	things  *Things[Thing]
+	data    []byte
+	entries []levelEntry
+	names   map[string]ThingRef
+	created []ThingRef // same order as entries
+}
+
+type levelEntry struct {
+	name        string
+	offset      int64 // of the entry
+	thing       levelField
+	refs, lists []levelField
+}
+
+// levelField is a JSON value and where it is in the file.
+type levelField struct {
+	key    string
+	value  json.RawMessage
+	offset int64
+}
+
+// parse reads the level into entries, remembering where everything is.
+func (level *levelLoader[Thing]) parse() error {
+	entries, err := level.array(level.data, 0)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		fields, err := level.object(e.value, e.offset)
+		if err != nil {
+			return err
+		}
+		entry := levelEntry{offset: e.offset}
+		for _, field := range fields {
+			switch field.key {
+			case "name":
+				if err := json.Unmarshal(field.value, &entry.name); err != nil {
+					return level.errorAt(field.offset, "name", errors.New("name must be a string"))
+				}
+			case "thing":
+				entry.thing = field
+			case "refs", "lists":
+				inner, err := level.object(field.value, field.offset)
+				if err != nil {
+					return err
+				}
+				if field.key == "refs" {
+					entry.refs = inner
+				} else {
+					entry.lists = inner
+				}
+			default:
+				return level.errorAt(field.offset, field.key, errors.New(`unknown key, expected "name", "thing", "refs" or "lists"`))
+			}
+		}
+		level.entries = append(level.entries, entry)
+	}
+	return nil
+}
+
+// create adds a Thing to the pool for every entry.
+func (level *levelLoader[Thing]) create() error {
+	for _, entry := range level.entries {
+		var thing Thing
+		if entry.thing.value != nil {
+			decoder := json.NewDecoder(bytes.NewReader(entry.thing.value))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(&thing); err != nil {
+				var typeErr *json.UnmarshalTypeError
+				if errors.As(err, &typeErr) {
+					return level.errorAt(entry.thing.offset+typeErr.Offset, level.field(entry, typeErr.Field),
+						fmt.Errorf("expected %v, got JSON %v", typeErr.Type, typeErr.Value))
+				}
+				return level.errorAt(entry.thing.offset, level.field(entry, "thing"), err)
+			}
+		}
+		if entry.name != "" {
+			if _, ok := level.names[entry.name]; ok {
+				return level.errorAt(entry.offset, "", fmt.Errorf("name %q is used more than once", entry.name))
+			}
+		}
+		ref := level.things.New(thing)
+		if ref == nilRef {
+			return level.errorAt(entry.offset, "", errors.New("the pool is full"))
+		}
+		level.created = append(level.created, ref)
+		if entry.name != "" {
+			level.names[entry.name] = ref
+		}
+	}
+	return nil
+}
+
+// link sets the refs and fills the lists, now that every name has a Thing.
+func (level *levelLoader[Thing]) link() error {
+	thingType := reflect.TypeFor[Thing]()
+	for i, entry := range level.entries {
+		self := level.created[i]
+		for _, field := range entry.refs {
+			name := level.field(entry, field.key)
+			offset, err := fieldPath(thingType, field.key, thingRefType)
+			if err != nil {
+				return level.errorAt(field.offset, name, err)
+			}
+			var target *string
+			if err := json.Unmarshal(field.value, &target); err != nil {
+				return level.errorAt(field.offset, name, errors.New("expected the name of a Thing or null"))
+			}
+			ref := nilRef
+			if target != nil {
+				if ref, err = level.lookup(*target); err != nil {
+					return level.errorAt(field.offset, name, err)
+				}
+			}
+			*fieldAt[ThingRef](level.things.get(self), offset) = ref
+		}
+		for _, field := range entry.lists {
+			name := level.field(entry, field.key)
+			offset, err := fieldPath(thingType, field.key, reflect.TypeFor[List[Thing]]())
+			if err != nil {
+				return level.errorAt(field.offset, name, err)
+			}
+			var members []string
+			if err := json.Unmarshal(field.value, &members); err != nil {
+				return level.errorAt(field.offset, name, errors.New("expected an array of names of Things"))
+			}
+			head := fieldAt[List[Thing]](level.things.get(self), offset)
+			if !head.isInitialized {
+				if head.group != 0 {
+					return level.errorAt(field.offset, name, errors.New("a Thing that is in a List can't own the same List field"))
+				}
+				head.init(self, level.things, offset, nil, 0)
+			}
+			for _, member := range members {
+				ref, err := level.lookup(member)
+				if err != nil {
+					return level.errorAt(field.offset, name, err)
+				}
+				node := fieldAt[List[Thing]](level.things.get(ref), offset)
+				if node.inList() {
+					return level.errorAt(field.offset, name, fmt.Errorf("%q is already in a List", member))
+				}
+				if node.isInitialized && node.owner() != self {
+					return level.errorAt(field.offset, name, fmt.Errorf("%q owns the same List field, it can't be in a List", member))
+				}
+				head.Append(ref)
+			}
+		}
+	}
+	return nil
+}
+
+func (level *levelLoader[Thing]) lookup(name string) (ThingRef, error) {
+	ref, ok := level.names[name]
+	if !ok {
+		return nilRef, fmt.Errorf("unknown name %q", name)
+	}
+	return ref, nil
+}
+
+// field names a field of the entry for errors.
+func (level *levelLoader[Thing]) field(entry levelEntry, field string) string {
+	if entry.name == "" {
+		return field
+	}
+	return entry.name + "." + field
+}
+
+func (level *levelLoader[Thing]) errorAt(offset int64, field string, err error) error {
+	line := 1 + bytes.Count(level.data[:min(int(offset), len(level.data))], []byte("\n"))
+	return &LevelError{Line: line, Field: field, Err: err}
+}
+
+// array returns the elements of the JSON array in data, which starts at base in the file.
+func (level *levelLoader[Thing]) array(data []byte, base int64) ([]levelField, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := level.expect(decoder, base, '['); err != nil {
+		return nil, err
+	}
+	var elements []levelField
+	for decoder.More() {
+		element := levelField{offset: base + skipSpace(data, decoder.InputOffset())}
+		if err := decoder.Decode(&element.value); err != nil {
+			return nil, level.syntaxError(decoder, base, err)
+		}
+		elements = append(elements, element)
+	}
+	return elements, nil
+}
+
+// object returns the fields of the JSON object in data, which starts at base in the file.
+func (level *levelLoader[Thing]) object(data []byte, base int64) ([]levelField, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := level.expect(decoder, base, '{'); err != nil {
+		return nil, err
+	}
+	var fields []levelField
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, level.syntaxError(decoder, base, err)
+		}
+		field := levelField{key: key.(string), offset: base + skipSpace(data, decoder.InputOffset())}
+		if err := decoder.Decode(&field.value); err != nil {
+			return nil, level.syntaxError(decoder, base, err)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func (level *levelLoader[Thing]) expect(decoder *json.Decoder, base int64, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return level.syntaxError(decoder, base, err)
+	}
+	if token != delim {
+		return level.errorAt(base, "", fmt.Errorf("expected %v, got %v", delim, token))
+	}
+	return nil
+}
+
+func (level *levelLoader[Thing]) syntaxError(decoder *json.Decoder, base int64, err error) error {
+	offset := base + decoder.InputOffset()
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		offset = base + syntaxErr.Offset
+	}
+	return level.errorAt(offset, "", err)
+}
+
+// skipSpace returns the offset of the next value in data, skipping whitespace and separators.
+func skipSpace(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) && strings.IndexByte(" \t\r\n,:", data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// fieldPath returns the offset of the field with the dotted path inside of t,
+// and an error if the field does not exist or is not of type want.
+func fieldPath(t reflect.Type, path string, want reflect.Type) (uintptr, error) {
+	var offset uintptr
+	for _, name := range strings.Split(path, ".") {
+		if t.Kind() != reflect.Struct {
+			return 0, fmt.Errorf("%v is not a struct", t)
+		}
+		field, ok := t.FieldByName(name)
+		if !ok || !field.IsExported() || len(field.Index) != 1 {
+			return 0, fmt.Errorf("%v does not have a field %q", t, name)
+		}
+		offset += field.Offset
+		t = field.Type
+	}
+	if t != want {
+		return 0, fmt.Errorf("expected a field of type %v, got %v", want, t)
+	}
+	return offset, nil
+}
@@ -0,0 +1,72 @@
+package ts_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+const testLevel = `[
+	{"name": "switch3", "thing": {"Combat": {"Hp": 5}}},
+	{"name": "door1", "refs": {"Target": "switch3", "Combat.LastHitBy": null}},
+	{"name": "sword"},
+	{"name": "potion"},
+	{"name": "player", "lists": {"Group": ["sword", "potion"]}}
+]`
+
+func TestLoadLevel(t *testing.T) {
+	things := ts.NewThings[Unit](16)
+	names, err := ts.LoadLevel(things, strings.NewReader(testLevel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := things.Get(names["switch3"]).Combat.Hp; got != 5 {
+		t.Fatalf("expected Hp 5, got %v", got)
+	}
+	if got := things.Get(names["door1"]).Target; got != names["switch3"] {
+		t.Fatalf("expected door1.Target to be switch3, got %v", got)
+	}
+	var group []ts.ThingRef
+	for ref := range things.Get(names["player"]).Group.Each() {
+		group = append(group, ref)
+	}
+	if len(group) != 2 || group[0] != names["sword"] || group[1] != names["potion"] {
+		t.Fatalf("expected player.Group to be [sword potion], got %v", group)
+	}
+}
+
+func TestLoadLevelErrors(t *testing.T) {
+	tests := []struct {
+		name, level string
+		line        int
+		field       string
+		contains    string
+	}{
+		{"unknown name", "[\n{\"name\": \"a\"},\n{\"name\": \"b\", \"refs\": {\"Target\": \"c\"}}\n]", 3, "b.Target", `unknown name "c"`},
+		{"unknown field", "[\n{\"name\": \"a\",\n \"refs\": {\"Targit\": \"a\"}}]", 3, "a.Targit", "does not have a field"},
+		{"type mismatch", "[\n{\"name\": \"a\",\n \"thing\": {\n\"Combat\": {\"Hp\": \"lots\"}}}]", 4, "a.Combat.Hp", "expected int"},
+		{"not a ThingRef", "[{\"name\": \"a\", \"refs\": {\"Combat.Hp\": \"a\"}}]", 1, "a.Combat.Hp", "expected a field of type"},
+		{"not a List", "[\n\n{\"name\": \"a\", \"lists\": {\"Target\": []}}]", 3, "a.Target", "expected a field of type"},
+		{"duplicate name", "[{\"name\": \"a\"},\n{\"name\": \"a\"}]", 2, "", "more than once"},
+		{"member twice", "[{\"name\": \"a\", \"lists\": {\"Group\": [\"b\"]}},\n{\"name\": \"c\", \"lists\": {\"Group\": [\"b\"]}},\n{\"name\": \"b\"}]", 2, "c.Group", "already in a List"},
+		{"syntax", "[\n{\"name\": \"a\",,}]", 2, "", "invalid character"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			things := ts.NewThings[Unit](16)
+			_, err := ts.LoadLevel(things, strings.NewReader(test.level))
+			var levelErr *ts.LevelError
+			if !errors.As(err, &levelErr) {
+				t.Fatalf("expected LevelError, got %v", err)
+			}
+			if levelErr.Line != test.line || levelErr.Field != test.field || !strings.Contains(err.Error(), test.contains) {
+				t.Fatalf("expected line %v field %q containing %q, got %v", test.line, test.field, test.contains, err)
+			}
+			for range things.Each() {
+				t.Fatal("expected no Things to be left after an error")
+			}
+		})
+	}
+}
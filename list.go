@@ -5,17 +5,33 @@ import (
 	"unsafe"
 )
 
+// OwnerPolicy decides what happens to the members of a List when its owner is deleted.
+type OwnerPolicy uint8
+
+const (
+	// OrphanMembers unlinks all the members, they stay alive. This is the default.
+	OrphanMembers OwnerPolicy = iota
+	// CascadeDelete deletes all the members together with the owner.
+	CascadeDelete
+	// Forbid logs an error and refuses to delete the owner while the List has members.
+	Forbid
+)
+
 // List is supposed to be embedded inside of your Thing type.
 // List is a circular intrusive linked list of Things.
 // In layman's terms: it's a list that wraps around and tracks ThingRef's of the items in the list.
 type List[Thing any] struct {
 	things        *Things[Thing]
 	isInitialized bool
-	owner         ThingRef
-	offset        uintptr // offset of this list within the Thing struct.
+	// the list this Thing belongs to, an index into things.listGroups. 0 if it is not linked.
+	group  uint32
+	offset uintptr // offset of this list within the Thing struct.
+	policy OwnerPolicy
 
-	// the first Thing in the list
+	// the first Thing in the list. Only tracked by the head of the list (the List field of the owner).
 	first ThingRef
+	// the number of Things in the list. Only tracked by the head.
+	length int
 	// the next Thing in the list. If we are the last thing in the list then next==first
 	next ThingRef
 	// the Thing before this Thing.
@@ -30,118 +46,433 @@ func (curr *List[Thing]) Append(things ...ThingRef) {
 }
 
 // Each iterates over the List and returns each ThingRef + Thing
+//
+// It is safe to PopSelf or Delete the current Thing while looping.
+// The next Thing is looked up before yielding, so the loop stops early
+// if the next Thing gets removed instead.
 func (curr *List[Thing]) Each() iter.Seq2[ThingRef, *Thing] {
+	return curr.each(false)
+}
+
+// each iterates over the List. If peek is true, the Things are read like Things.Peek.
+func (curr *List[Thing]) each(peek bool) iter.Seq2[ThingRef, *Thing] {
 	return func(yield func(ThingRef, *Thing) bool) {
 		if !curr.isInitialized {
-			if logger != nil {
-				logger.Warn("Range over uninitialized list", "file", getParentCaller(0))
+			if !releaseMode {
+				logWarn(0, "Range over uninitialized list")
 			}
 			return
 		}
 		if curr.first == nilRef {
 			return // empty uninitialized list.
 		}
-		idx := 0
 		current := curr.first
 		for {
+			next := curr.getListDataFromThing(current).next
+			isLast := next == curr.first
+			var thing *Thing
+			if peek {
+				thing, _ = curr.things.Peek(current)
+			} else {
+				thing = curr.things.get(current)
+			}
+			if !yield(current, thing) {
+				return
+			}
+			if isLast || !curr.has(next) {
+				return
+			}
+			current = next
+		}
+	}
+}
+
+// Backward iterates over the List from the last Thing to the first.
+//
+// Like Each, it is safe to PopSelf or Delete the current Thing while looping.
+func (curr *List[Thing]) Backward() iter.Seq2[ThingRef, *Thing] {
+	return func(yield func(ThingRef, *Thing) bool) {
+		if !curr.isInitialized {
+			if !releaseMode {
+				logWarn(0, "Range over uninitialized list")
+			}
+			return
+		}
+		if curr.first == nilRef {
+			return
+		}
+		current := curr.getListDataFromThing(curr.first).prev
+		for {
+			prev := curr.getListDataFromThing(current).prev
+			isFirst := current == curr.first
+			if !yield(current, curr.things.get(current)) {
+				return
+			}
+			if isFirst || !curr.has(prev) {
+				return
+			}
+			current = prev
+		}
+	}
+}
+
+// EachFrom iterates over the List starting at ref, wrapping around past the end,
+// until every Thing has been visited once.
+//
+// Like Each, it is safe to PopSelf or Delete the current Thing while looping.
+// The loop stops early if the next Thing or the last one, the Thing before ref, get removed instead.
+func (curr *List[Thing]) EachFrom(ref ThingRef) iter.Seq2[ThingRef, *Thing] {
+	return func(yield func(ThingRef, *Thing) bool) {
+		if !curr.isInitialized {
+			if !releaseMode {
+				logWarn(0, "Range over uninitialized list")
+			}
+			return
+		}
+		if !curr.has(ref) {
+			if !releaseMode {
+				logWarn(0, "Tried to range from Thing that is not inside the list")
+			}
+			return
+		}
+		// ref itself can be popped while looping, so the loop stops after the Thing before it.
+		last := curr.getListDataFromThing(ref).prev
+		current := ref
+		for {
+			next := curr.getListDataFromThing(current).next
+			isLast := current == last
 			if !yield(current, curr.things.get(current)) {
 				return
 			}
-			current = curr.getListDataFromThing(current).next
-			idx++
-			if current == curr.first {
-				break
+			if isLast || !curr.has(next) || !curr.has(last) {
+				return
 			}
+			current = next
 		}
 	}
 }
 
+// Cycle follows the circular List forever, starting at the first Thing.
+// Useful for round-robin scheduling, like turn order. Break out of the loop to stop.
+//
+// It is safe to PopSelf or Delete the current Thing while looping,
+// the cycle continues with the Thing that was after it.
+// The loop stops when the List becomes empty, or when it can't find where to continue.
+func (curr *List[Thing]) Cycle() iter.Seq2[ThingRef, *Thing] {
+	return func(yield func(ThingRef, *Thing) bool) {
+		if !curr.isInitialized {
+			if !releaseMode {
+				logWarn(0, "Range over uninitialized list")
+			}
+			return
+		}
+		current := curr.first
+		for current != nilRef {
+			next := curr.getListDataFromThing(current).next
+			if !yield(current, curr.things.get(current)) {
+				return
+			}
+			switch {
+			case curr.has(next):
+				current = next
+			case curr.has(current): // next was removed, but we are still inside
+				current = curr.getListDataFromThing(current).next
+			default:
+				return
+			}
+		}
+	}
+}
+
+// RemoveWhere pops every Thing in the List for which remove returns true.
+// The popped Things stay alive. It returns the number of popped Things.
+func (curr *List[Thing]) RemoveWhere(remove func(ref ThingRef, thing *Thing) bool) int {
+	if !curr.isHead("RemoveWhere on", 0) {
+		return 0
+	}
+	removed := 0
+	for ref, thing := range curr.Each() {
+		if remove(ref, thing) {
+			curr.getListDataFromThing(ref).pop()
+			removed++
+		}
+	}
+	return removed
+}
+
 // PopSelf removes current Thing from List.
 func (curr *List[Thing]) PopSelf() {
-	if curr.owner == nilRef {
-		if logger != nil {
-			logger.Warn("Tried to Pop from uninitialized list", "file", getParentCaller(0))
+	if curr.group == 0 {
+		if !releaseMode {
+			logWarn(0, "Tried to Pop from uninitialized list")
 		}
 		return
 	}
-	if curr.first == nilRef {
-		if logger != nil {
-			logger.Warn("Tried to Pop from empty list", "file", getParentCaller(0))
+	if !curr.inList() {
+		if !releaseMode {
+			logWarn(0, "Tried to Pop Thing that is not inside a list")
 		}
 		return
 	}
+	curr.pop()
+}
 
-	next := curr.getListDataFromThing(curr.next)
-	currRef := next.prev
-	// single element case
-	if curr.next == currRef {
+// pop unlinks this Thing from the list it is inside of.
+func (curr *List[Thing]) pop() {
+	curr.head().length--
+	curr.unlink()
+	// clear this node, but keep it usable if it is also the head of the list.
+	if curr.isInitialized {
+		curr.next, curr.prev = nilRef, nilRef
+	} else {
+		curr.things.releaseGroup(curr.group)
 		*curr = List[Thing]{}
+	}
+}
+
+// InsertNext inserts the Thing after the this Thing.
+// It does not do anything if this Thing is not inside a list.
+func (curr *List[Thing]) InsertNext(newThingRef ThingRef) {
+	if curr.group == 0 {
+		if !releaseMode {
+			logWarn(0, "Tried to Insert into uninitialized list")
+		}
+		return
+	}
+	if !curr.inList() {
+		if !releaseMode {
+			logWarn(0, "Tried to Insert next to Thing that is not inside a list")
+		}
+		return
+	}
+	if !curr.canLink(newThingRef, 0) {
 		return
 	}
 
-	first := curr.getListDataFromThing(curr.first)
-	prev := curr.getListDataFromThing(curr.prev)
+	curr.adopt(newThingRef)
+	// insert. If we are the last element, next is first so the circle stays closed.
+	curr.linkAfter(curr.self(), newThingRef)
+}
 
-	// pop current
-	prev.next = curr.next
-	next.prev = curr.prev
+// InsertBefore inserts the Thing before this Thing.
+// It does not do anything if this Thing is not inside a list.
+func (curr *List[Thing]) InsertBefore(newThingRef ThingRef) {
+	if !curr.isMember("Insert before", 0) || !curr.canLink(newThingRef, 0) {
+		return
+	}
+	head := curr.head()
+	self := curr.self()
+	curr.adopt(newThingRef)
+	curr.linkAfter(curr.prev, newThingRef)
+	if head.first == self {
+		head.first = newThingRef
+	}
+}
 
-	// if removing first element, move head
-	if currRef == curr.first {
-		curr.first = curr.next
+// Prepend adds the Things to the front of the List, keeping their order.
+//
+//	list.Prepend(a, b) // a, b, ...rest of the list
+func (curr *List[Thing]) Prepend(things ...ThingRef) {
+	if !curr.isHead("Prepend to", 0) {
+		return
 	}
+	for i := len(things) - 1; i >= 0; i-- {
+		if !curr.canLink(things[i], 0) {
+			continue
+		}
+		curr.adopt(things[i])
+		curr.linkLast(things[i])
+		// the ring is circular, so the last Thing becomes the first by moving the head.
+		curr.first = things[i]
+	}
+}
 
-	// if removing last element, update first.prev
-	if currRef == first.prev {
-		first.prev = curr.prev
+// MoveToFront moves this Thing to the front of the List it is inside of.
+func (curr *List[Thing]) MoveToFront() {
+	if !curr.isMember("MoveToFront", 0) {
+		return
 	}
-	// clear this node
-	*curr = List[Thing]{}
+	head := curr.head()
+	self := curr.self()
+	if head.first == self {
+		return
+	}
+	curr.unlink()
+	head.linkLast(self)
+	head.first = self
+}
 
+// MoveToBack moves this Thing to the back of the List it is inside of.
+func (curr *List[Thing]) MoveToBack() {
+	if !curr.isMember("MoveToBack", 0) {
+		return
+	}
+	head := curr.head()
+	self := curr.self()
+	if curr.getListDataFromThing(head.first).prev == self {
+		return // already last
+	}
+	curr.unlink()
+	head.linkLast(self)
 }
 
-// InsertNext inserts the Thing after the this Thing.
-// It does not do anything if list is empty or uninitialized.
-func (curr *List[Thing]) InsertNext(newThingRef ThingRef) {
-	if newThingRef == nilRef {
-		if logger != nil {
-			logger.Warn("Tried to insert NilRef into list", "file", getParentCaller(0))
+// MoveAfter moves this Thing after ref. Both must be inside the same List.
+func (curr *List[Thing]) MoveAfter(ref ThingRef) {
+	if !curr.isMember("MoveAfter", 0) || !curr.isSibling(ref, "MoveAfter", 0) {
+		return
+	}
+	self := curr.self()
+	if ref == self {
+		return
+	}
+	// also moves the head if we were first and ref was last.
+	curr.unlink()
+	curr.linkAfter(ref, self)
+}
+
+// Swap swaps the positions of this Thing and ref. Both must be inside the same List.
+func (curr *List[Thing]) Swap(ref ThingRef) {
+	if !curr.isMember("Swap", 0) || !curr.isSibling(ref, "Swap", 0) {
+		return
+	}
+	self := curr.self()
+	if ref == self {
+		return
+	}
+	head := curr.head()
+	first := head.first
+	other := curr.getListDataFromThing(ref)
+	switch {
+	case curr.next == ref: // self, other -> other, self
+		curr.unlink()
+		curr.linkAfter(ref, self)
+	case other.next == self: // other, self -> self, other
+		other.unlink()
+		curr.linkAfter(self, ref)
+	default:
+		prev := curr.prev
+		curr.unlink()
+		curr.linkAfter(ref, self)
+		other.unlink()
+		curr.linkAfter(prev, ref)
+	}
+	// unlinking may have moved the head, put it where it belongs.
+	switch first {
+	case self:
+		head.first = ref
+	case ref:
+		head.first = self
+	default:
+		head.first = first
+	}
+}
+
+// Reverse reverses the order of the List.
+func (curr *List[Thing]) Reverse() {
+	if !curr.isHead("Reverse", 0) || curr.first == nilRef {
+		return
+	}
+	last := curr.getListDataFromThing(curr.first).prev
+	ref := curr.first
+	for {
+		node := curr.getListDataFromThing(ref)
+		node.next, node.prev = node.prev, node.next
+		ref = node.prev // the old next
+		if ref == curr.first {
+			break
 		}
 	}
-	if curr.owner == nilRef {
-		if logger != nil {
-			logger.Warn("Tried to Insert into uninitialized list", "file", getParentCaller(0))
+	curr.first = last
+}
+
+// Clear removes all the Things from the List. They stay alive.
+func (curr *List[Thing]) Clear() {
+	if !curr.isHead("Clear", 0) {
+		return
+	}
+	for curr.first != nilRef {
+		curr.getListDataFromThing(curr.first).pop()
+	}
+}
+
+// Splice moves all the Things of this List to the back of other, keeping their order.
+// Both Lists must be the same field of Things in the same pool.
+// The owner stays behind if it is inside its own List.
+//
+// It is O(1). The moved Things find their new owner the next time it is needed.
+func (curr *List[Thing]) Splice(other *List[Thing]) {
+	if !curr.isHead("Splice", 0) || !other.isHead("Splice into", 0) {
+		return
+	}
+	if curr.things != other.things || curr.offset != other.offset {
+		if !releaseMode {
+			logWarn(0, "Tried to Splice into a different List field")
 		}
+		return
+	}
+	if curr.owner() == other.owner() {
+		return
+	}
+	// the owner can't be inside another list in the same field.
+	if curr.inList() {
+		curr.pop()
 	}
 	if curr.first == nilRef {
-		if logger != nil {
-			logger.Warn("Tried to Insert into empty list", "file", getParentCaller(0))
-		}
+		return
 	}
+	// the members point to the old group, which now leads to other. The owner gets a new one.
+	things, moved := curr.things, curr.group
+	things.listGroups[moved].parent = other.group
+	things.retainGroup(other.group)
+	curr.group = things.newGroup(things.listGroups[moved].owner)
+	things.releaseGroup(moved)
 
-	newThing := curr.getListDataFromThing(newThingRef)
-	// must be popped from list before Thing is deleted.
-	curr.things.insideLists[newThingRef] = append(curr.things.insideLists[newThingRef], newThing)
-	next := curr.getListDataFromThing(curr.next)
-	currThingRef := next.prev
-	// insert
-	newThing.next = curr.next
-	newThing.prev = currThingRef
-	next.prev = newThingRef
-	curr.next = newThingRef
+	other.length += curr.length
+	curr.length = 0
+	if other.first == nilRef {
+		other.first = curr.first
+	} else {
+		// otherLast <-> first ... last <-> otherFirst
+		otherFirst := curr.getListDataFromThing(other.first)
+		otherLast := curr.getListDataFromThing(otherFirst.prev)
+		first := curr.getListDataFromThing(curr.first)
+		last := curr.getListDataFromThing(first.prev)
+
+		otherLast.next, last.next = curr.first, other.first
+		first.prev, otherFirst.prev = otherFirst.prev, first.prev
+	}
+	curr.first = nilRef
+}
+
+// Contains reports whether ref is inside this List. It is O(1).
+func (curr *List[Thing]) Contains(ref ThingRef) bool {
+	if curr.group == 0 {
+		if !releaseMode {
+			logWarn(0, "Tried to check Contains on uninitialized list")
+		}
+		return false
+	}
+	return curr.has(ref)
+}
 
-	// check if inserted after last element
-	first := curr.getListDataFromThing(curr.first)
-	if curr.next == curr.first { // inserted at end, update circle
-		first.prev = newThingRef
+// Len returns the number of elements in the List. It is O(1).
+// It can be called on the owner or on any Thing inside the List.
+func (curr *List[Thing]) Len() int {
+	if curr.group == 0 {
+		if !releaseMode {
+			logWarn(0, "Attempt to get Len of uninitialized list")
+		}
+		return 0
 	}
+	return curr.head().length
 }
 
-// Count counts the number of elements in the List
+// Count counts the number of elements in the List by walking it.
+// Prefer Len, which is O(1).
 func (curr *List[Thing]) Count() int {
 	if !curr.isInitialized {
-		if logger != nil {
-			logger.Warn("Attempt to Count uninitialized list", "file", getParentCaller(0))
+		if !releaseMode {
+			logWarn(0, "Attempt to Count uninitialized list")
 		}
 		return 0
 	}
@@ -153,139 +484,327 @@ func (curr *List[Thing]) Count() int {
 }
 
 // Init initializes the List. It must be called before adding Things.
-func (curr *List[Thing]) Init(selfRef ThingRef, things *Things[Thing]) (self *Thing) {
+//
+// The optional OwnerPolicy decides what happens to the members when the owner is deleted.
+// The default is OrphanMembers.
+//
+//	things.Get(Plr).Inventory.Init(Plr, things, ts.CascadeDelete) // items die with the player
+//
+// Init must be called on the List inside the pool, never on a copy of the Thing.
+// Use ListField to initialize Lists by ThingRef alone instead.
+func (curr *List[Thing]) Init(selfRef ThingRef, things *Things[Thing], policy_OPTIONAL ...OwnerPolicy) (self *Thing) {
 	if !things.IsNotNil(selfRef) {
 		return things.get(nilRef)
 	}
-	if curr.owner != nilRef {
-		if logger != nil {
-			logger.Error("Cannot Initialize a if Thing is already part of this list field. Add a new List field and initialize that instead.", "file", getParentCaller(0))
-		}
-		return things.get(nilRef)
-	}
-	curr.isInitialized = true
-	curr.owner = selfRef
-	curr.things = things
 	owner := things.get(selfRef)
 
-	// compute and store the offset of this List field inside the owner struct
-	curr.offset = uintptr(unsafe.Pointer(curr)) - uintptr(unsafe.Pointer(owner))
+	// compute the offset of this List field inside the owner struct
+	offset := uintptr(unsafe.Pointer(curr)) - uintptr(unsafe.Pointer(owner))
 
 	// validate offset. the field must fit inside the owner object
 	ownerSize := unsafe.Sizeof(*owner)
 	listSize := unsafe.Sizeof(*curr)
-	if curr.offset+listSize > ownerSize {
-		*curr = List[Thing]{} // uninitialize
-		if logger != nil {
-			logger.Error("Incorrect owner ThingRef passed", "file", getParentCaller(0))
-			return
+	if offset+listSize > ownerSize {
+		if !releaseMode {
+			logError(0, "Incorrect owner ThingRef passed")
 		}
+		return things.get(nilRef)
+	}
+	if !curr.init(selfRef, things, offset, policy_OPTIONAL, 0) {
+		return things.get(nilRef)
 	}
 	return owner
 }
 
+// init initializes the List with an offset that is known to be valid.
+func (curr *List[Thing]) init(selfRef ThingRef, things *Things[Thing], offset uintptr, policy_OPTIONAL []OwnerPolicy, skip int) bool {
+	if curr.group != 0 {
+		if !releaseMode {
+			logError(1+skip, "Cannot Initialize a if Thing is already part of this list field. Add a new List field and initialize that instead.")
+		}
+		return false
+	}
+	curr.isInitialized = true
+	curr.group = things.newGroup(selfRef)
+	curr.things = things
+	curr.offset = offset
+	if len(policy_OPTIONAL) > 0 {
+		curr.policy = policy_OPTIONAL[0]
+	}
+	// members must be released before the owner is deleted.
+	things.listOffsets = registerOffset(things.listOffsets, offset)
+	return true
+}
+
+// IsLinked reports whether the List was initialized, or is inside of a List.
+// Owner and Len can only be called on Lists that are linked.
+func (curr *List[Thing]) IsLinked() bool {
+	return curr.group != 0
+}
+
 // Owner returns the Owner of the list
 func (curr *List[Thing]) Owner() ThingRef {
-	if curr.owner == nilRef {
-		if logger != nil {
-			logger.Warn("Tried to get Owner of uninitialized list", "caller", getParentCaller(0))
+	if curr.group == 0 {
+		if !releaseMode {
+			logWarn(0, "Tried to get Owner of uninitialized list")
 		}
 	}
-	return curr.owner
+	return curr.owner()
 }
 
 // First returns the First thing inside the List.
 // Thing is guaranteed to be not nil.
 func (curr *List[Thing]) First() *Thing {
-	if curr.owner == nilRef {
-		if logger != nil {
-			logger.Warn("Tried to get First Thing in uninitialized list", "caller", getParentCaller(0))
+	if curr.group == 0 {
+		if !releaseMode {
+			logWarn(0, "Tried to get First Thing in uninitialized list")
 		}
 	}
-	return curr.things.get(curr.first)
+	return curr.get(curr.head().first)
 }
 
 // First returns the Previous thing inside the List.
 // Thing is guaranteed to be not nil.
 func (curr *List[Thing]) Prev() *Thing {
-	if curr.owner == nilRef {
-		if logger != nil {
-			logger.Warn("Tried to get Previous Thing in uninitialized list", "caller", getParentCaller(0))
+	if curr.group == 0 {
+		if !releaseMode {
+			logWarn(0, "Tried to get Previous Thing in uninitialized list")
 		}
 	}
-	return curr.things.get(curr.prev)
+	return curr.get(curr.prev)
 }
 
 // First returns the Next thing inside the List.
 // Thing is guaranteed to be not nil.
 func (curr *List[Thing]) Next() *Thing {
-	if curr.owner == nilRef {
-		if logger != nil {
-			logger.Warn("Tried to get Next Thing in uninitialized list", "caller", getParentCaller(0))
+	if curr.group == 0 {
+		if !releaseMode {
+			logWarn(0, "Tried to get Next Thing in uninitialized list")
 		}
 	}
-	return curr.things.get(curr.next)
+	return curr.get(curr.next)
 }
 
 // First returns the Last thing inside the List.
 // Thing is guaranteed to be not nil.
 func (curr *List[Thing]) Last() *Thing {
-	if curr.owner == nilRef {
-		if logger != nil {
-			logger.Warn("Tried to get Last Thing in uninitialized list", "caller", getParentCaller(0))
+	if curr.group == 0 {
+		if !releaseMode {
+			logWarn(0, "Tried to get Last Thing in uninitialized list")
 		}
 	}
 	// first -> prev == last
-	last := curr.getListDataFromThing(curr.first).prev
-	return curr.things.get(last)
+	last := curr.getListDataFromThing(curr.head().first).prev
+	return curr.get(last)
 }
 
 // get List from this Thing.
+// List fields are not scanned by EnableRefNulling, so the Thing is read like Things.Peek.
 func (curr *List[Thing]) getListDataFromThing(thingRef ThingRef) *List[Thing] {
-	thing := curr.things.get(thingRef)
+	if curr.things == nil {
+		return fieldAt[List[Thing]](new(Thing), curr.offset)
+	}
+	thing, _ := curr.things.Peek(thingRef)
 	// add the stored offset to the Thing pointer to get pointer to the embedded List field
-	fieldPtr := unsafe.Add(unsafe.Pointer(thing), curr.offset)
-	return (*List[Thing])(fieldPtr)
+	return fieldAt[List[Thing]](thing, curr.offset)
+}
+
+// get is the same as Things.get, but also works on uninitialized lists.
+func (curr *List[Thing]) get(thingRef ThingRef) *Thing {
+	if curr.things == nil {
+		return new(Thing)
+	}
+	return curr.things.get(thingRef)
+}
+
+// head returns the List field of the owner. The head tracks the first Thing in the list.
+func (curr *List[Thing]) head() *List[Thing] {
+	return curr.getListDataFromThing(curr.owner())
+}
+
+// owner returns the owner of the list this Thing is inside of, following Splices.
+func (curr *List[Thing]) owner() ThingRef {
+	if curr.group == 0 {
+		return nilRef
+	}
+	if root := curr.things.rootGroup(curr.group); root != curr.group {
+		curr.things.retainGroup(root)
+		curr.things.releaseGroup(curr.group)
+		curr.group = root
+	}
+	return curr.things.listGroups[curr.group].owner
 }
+
+// inList reports whether this Thing is inside of a list.
+func (curr *List[Thing]) inList() bool {
+	return curr.next != nilRef
+}
+
+// hasOtherMembers reports whether the list has members besides the owner.
+func (curr *List[Thing]) hasOtherMembers() bool {
+	if curr.first == nilRef {
+		return false
+	}
+	owner := curr.owner()
+	onlyOwner := curr.first == owner && curr.next == owner
+	return !onlyOwner
+}
+
+// releaseMembers empties the list of an owner that is being deleted, following the OwnerPolicy.
+func (curr *List[Thing]) releaseMembers() {
+	for curr.first != nilRef {
+		member := curr.first
+		if curr.policy == CascadeDelete && member != curr.owner() {
+			curr.things.del(member) // pops the member
+		}
+		// orphaned, the owner itself, or the member refused to be deleted.
+		if curr.first == member {
+			curr.getListDataFromThing(member).pop()
+		}
+	}
+}
+
+// canLink reports whether newThingRef can be linked into this list.
+// It logs the reason if it can't.
+func (curr *List[Thing]) canLink(newThingRef ThingRef, skip int) bool {
+	if !curr.things.IsNotNil(newThingRef) {
+		if !releaseMode {
+			logWarn(1+skip, "Tried to insert NilRef into list")
+		}
+		return false
+	}
+	newThing := curr.getListDataFromThing(newThingRef)
+	if newThing.inList() {
+		if !releaseMode {
+			logWarn(1+skip, "Tried to insert Thing that is already inside a list. PopSelf it first.")
+		}
+		return false
+	}
+	// the List field of the new Thing is the head of another list.
+	if newThing.isInitialized && newThing.owner() != curr.owner() {
+		if !releaseMode {
+			logWarn(1+skip, "Tried to insert Thing that owns a list in the same field")
+		}
+		return false
+	}
+	return true
+}
+
 func (curr *List[Thing]) append(newThingRef ThingRef) {
-	if !curr.isInitialized || !curr.things.isInBounds(newThingRef) || !curr.things.isAlive(newThingRef) {
-		logger.Warn("Append to uninitialized list", "file", getParentCaller(1))
+	if !curr.isInitialized {
+		if !releaseMode {
+			logWarn(1, "Append to uninitialized list")
+		}
+		return
+	}
+	if !curr.canLink(newThingRef, 1) {
 		return
 	}
-	// must be popped from list before deletion
+	curr.adopt(newThingRef)
+	curr.linkLast(newThingRef)
+}
 
+// adopt makes newThingRef a member of this list, without linking it into the circle.
+func (curr *List[Thing]) adopt(newThingRef ThingRef) {
 	newThing := curr.getListDataFromThing(newThingRef)
-	curr.things.insideLists[newThingRef] = append(curr.things.insideLists[newThingRef], newThing)
 	newThing.things = curr.things
 	newThing.offset = curr.offset
-	newThing.owner = curr.owner
+	// follow Splices, so the new Thing joins the group of the list it is inside of now.
+	curr.owner()
+	// the owner is already in its own group.
+	if newThing.group != curr.group {
+		newThing.group = curr.group
+		curr.things.retainGroup(curr.group)
+	}
+	curr.head().length++
+}
 
+// linkLast links ref at the end of the list. Must be called on the head.
+func (curr *List[Thing]) linkLast(ref ThingRef) {
 	// if list was empty
-	if curr.first == nilRef && curr.next == nilRef && curr.prev == nilRef {
+	if curr.first == nilRef {
 		// The only thing in the list. Links to itself.
-		newThing.first = newThingRef
-		newThing.next = newThingRef
-		newThing.prev = newThingRef
-
+		node := curr.getListDataFromThing(ref)
+		node.next, node.prev = ref, ref
 		// update Head of the list
-		curr.first = newThingRef
-		curr.next = newThingRef
-		curr.prev = newThingRef
+		curr.first = ref
 		return
 	}
-
-	// add NewThing between first and last
-	firstThing := curr.getListDataFromThing(curr.first)
 	// Last is just First->Prev
-	last := firstThing.prev
-	lastThing := curr.getListDataFromThing(last)
+	curr.linkAfter(curr.getListDataFromThing(curr.first).prev, ref)
+}
+
+// linkAfter links ref into the circle after at.
+func (curr *List[Thing]) linkAfter(at, ref ThingRef) {
+	node := curr.getListDataFromThing(ref)
+	atNode := curr.getListDataFromThing(at)
+	// at <-> ref <-> next
+	node.prev = at
+	node.next = atNode.next
+	curr.getListDataFromThing(atNode.next).prev = ref
+	atNode.next = ref
+}
+
+// unlink removes this Thing from the circle, and moves the head if needed.
+// The links of this Thing are left as they were.
+func (curr *List[Thing]) unlink() {
+	head := curr.head()
+	self := curr.self()
+	if curr.next == self { // single element case
+		head.first = nilRef
+		return
+	}
+	curr.getListDataFromThing(curr.prev).next = curr.next
+	curr.getListDataFromThing(curr.next).prev = curr.prev
+	// if removing first element, move head
+	if head.first == self {
+		head.first = curr.next
+	}
+}
 
-	// Last <-> New <-> First
-	newThing.first = curr.first
-	newThing.prev = last
-	newThing.next = curr.first
+// self returns the ref of the Thing this node belongs to. Only works inside a list.
+func (curr *List[Thing]) self() ThingRef {
+	return curr.getListDataFromThing(curr.next).prev
+}
 
-	// update neighbors
-	lastThing.next = newThingRef
-	firstThing.prev = newThingRef
+// has is the same as Contains but does not trigger a log.
+func (curr *List[Thing]) has(ref ThingRef) bool {
+	if curr.things == nil || !curr.things.IsNotNil(ref) {
+		return false
+	}
+	node := curr.getListDataFromThing(ref)
+	return node.inList() && node.owner() == curr.owner()
+}
+
+// isHead reports whether this List is initialized, and logs if it isn't.
+func (curr *List[Thing]) isHead(action string, skip int) bool {
+	if !curr.isInitialized {
+		if !releaseMode {
+			logWarn(1+skip, "Tried to "+action+" uninitialized list")
+		}
+		return false
+	}
+	return true
+}
+
+// isMember reports whether this Thing is inside a list, and logs if it isn't.
+func (curr *List[Thing]) isMember(action string, skip int) bool {
+	if !curr.inList() {
+		if !releaseMode {
+			logWarn(1+skip, "Tried to "+action+" Thing that is not inside a list")
+		}
+		return false
+	}
+	return true
+}
+
+// isSibling reports whether ref is inside the same list as this Thing, and logs if it isn't.
+func (curr *List[Thing]) isSibling(ref ThingRef, action string, skip int) bool {
+	if !curr.things.IsNotNil(ref) || !curr.getListDataFromThing(ref).inList() || curr.getListDataFromThing(ref).owner() != curr.owner() {
+		if !releaseMode {
+			logWarn(1+skip, "Tried to "+action+" with Thing that is not inside the same list")
+		}
+		return false
+	}
+	return true
 }
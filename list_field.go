@@ -0,0 +1,88 @@
+package ts
+
+import "unsafe"
+
+// Lists gives access to one List field of every Thing in a pool.
+// Create it with ListField.
+type Lists[Thing any] struct {
+	things *Things[Thing]
+	offset uintptr
+	valid  bool
+}
+
+// ListField registers a List field of Thing and returns a Lists to initialize and access it by ThingRef alone.
+// The offset of the field is derived and validated once,
+// so a List can't be initialized on a copy of the Thing by accident.
+//
+// field must return a pointer to a List field of the Thing it is given, nothing else.
+//
+//	inventory := ts.ListField(things, func(t *Thing) *ts.List[Thing] { return &t.Inventory })
+//	inventory.Init(Plr)
+//	inventory.Get(Plr).Append(item1, item2)
+func ListField[Thing any](things *Things[Thing], field func(t *Thing) *List[Thing]) Lists[Thing] {
+	// the nil Thing lives inside the pool too, so it can be used to find the offset.
+	base := things.at(0)
+	offset, ok := fieldOffset(base, field(base))
+	// the offset must be the same for every Thing.
+	if ok && things.maxThings > 1 {
+		other := things.at(1)
+		otherOffset, otherOk := fieldOffset(other, field(other))
+		ok = otherOk && otherOffset == offset
+	}
+	if !ok {
+		if !releaseMode {
+			logError(0, "ListField must return a List field of the Thing it is given")
+		}
+		return Lists[Thing]{}
+	}
+	things.listOffsets = registerOffset(things.listOffsets, offset)
+	return Lists[Thing]{things: things, offset: offset, valid: true}
+}
+
+// fieldOffset returns the offset of list inside thing, and false if list is not inside thing.
+func fieldOffset[Thing any](thing *Thing, list *List[Thing]) (uintptr, bool) {
+	start := uintptr(unsafe.Pointer(thing))
+	field := uintptr(unsafe.Pointer(list))
+	if list == nil || field < start || field+unsafe.Sizeof(*list) > start+unsafe.Sizeof(*thing) {
+		return 0, false
+	}
+	return field - start, true
+}
+
+// Init initializes the List field of the Thing. It must be called before adding Things.
+// The optional OwnerPolicy works like in List.Init.
+func (lists Lists[Thing]) Init(selfRef ThingRef, policy_OPTIONAL ...OwnerPolicy) (self *Thing) {
+	if !lists.valid {
+		if !releaseMode {
+			logError(0, "Tried to Init with an invalid ListField")
+		}
+		return new(Thing)
+	}
+	if !lists.things.IsNotNil(selfRef) {
+		if !releaseMode {
+			logWarn(0, "Tried to Init List of inactive Thing")
+		}
+		return lists.things.get(nilRef)
+	}
+	self = lists.things.get(selfRef)
+	if !lists.Get(selfRef).init(selfRef, lists.things, lists.offset, policy_OPTIONAL, 0) {
+		return lists.things.get(nilRef)
+	}
+	return self
+}
+
+// Get returns the List field of the Thing behind the ThingRef.
+// It is guaranteed to never be nil.
+// Like Things.Get, the pointer should not be stored.
+func (lists Lists[Thing]) Get(ref ThingRef) *List[Thing] {
+	if !lists.valid {
+		if !releaseMode {
+			logError(0, "Tried to Get with an invalid ListField")
+		}
+		return new(List[Thing])
+	}
+	if !lists.things.IsNotNil(ref) && !releaseMode {
+		logWarn(0, "Derefence of NilRef.")
+	}
+	return fieldAt[List[Thing]](lists.things.get(ref), lists.offset)
+}
@@ -0,0 +1,67 @@
+package ts_test
+
+import (
+	"slices"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+func TestListFieldInitByRef(t *testing.T) {
+	things := ts.NewThings[Thing](16)
+	inventory := ts.ListField(things, func(t *Thing) *ts.List[Thing] { return &t.Inventory })
+
+	plr := things.New(Thing{Kind: KindPlayer})
+	if self := inventory.Init(plr, ts.CascadeDelete); self != things.Get(plr) {
+		t.Fatal("expected Init to return the owner")
+	}
+	a := things.New(Thing{Kind: KindItem, ItemID: 1})
+	b := things.New(Thing{Kind: KindItem, ItemID: 2})
+	inventory.Get(plr).Append(a, b)
+
+	// same List as the field itself
+	if got, want := itemIDs(things, plr), []int32{1, 2}; !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if inventory.Get(a) != &things.Get(a).Inventory {
+		t.Fatal("expected Get to return the List field of the Thing")
+	}
+	// policy was passed through
+	things.Delete(plr)
+	if things.IsNotNil(a) || things.IsNotNil(b) {
+		t.Fatal("expected members to be deleted with the owner")
+	}
+}
+
+var globalList ts.List[Thing]
+
+func TestListFieldMisuse(t *testing.T) {
+	things := ts.NewThings[Thing](16)
+	plr := things.New(Thing{Kind: KindPlayer})
+	item := things.New(Thing{Kind: KindItem})
+
+	// not a field of the Thing it was given
+	global := ts.ListField(things, func(t *Thing) *ts.List[Thing] { return &globalList })
+	global.Init(plr)
+	global.Get(plr).Append(item)
+	if globalList.Owner() != (ts.ThingRef{}) {
+		t.Fatal("expected invalid ListField to not touch the global List")
+	}
+
+	// a field of a copy of the Thing
+	copied := ts.ListField(things, func(t *Thing) *ts.List[Thing] {
+		c := *t
+		return &c.Inventory
+	})
+	copied.Init(plr)
+	if things.Get(plr).Inventory.Owner() != (ts.ThingRef{}) {
+		t.Fatal("expected invalid ListField to not initialize the List")
+	}
+
+	// Init by ref on a copy is rejected too
+	c := *things.Get(plr)
+	c.Inventory.Init(plr, things)
+	if c.Inventory.Owner() != (ts.ThingRef{}) {
+		t.Fatal("expected List.Init on a copy of the Thing to be rejected")
+	}
+}
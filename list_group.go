@@ -0,0 +1,65 @@
+package ts
+
+// listGroup is the List a Thing is inside of. All the members of a List point to the same group,
+// so Splice can move them to another List in O(1) by forwarding the group,
+// and the new owner of a member is found lazily the next time it is needed.
+type listGroup struct {
+	owner ThingRef
+	// the group the members were spliced into, 0 if the group is still a List of its own.
+	parent uint32
+	// the number of List fields and groups that point to this group. It is reused at 0.
+	refs uint32
+}
+
+// newGroup returns a group for the List of owner, with one reference.
+func (things *Things[Thing]) newGroup(owner ThingRef) uint32 {
+	var id uint32
+	if n := len(things.freeGroups); n > 0 {
+		id = things.freeGroups[n-1]
+		things.freeGroups = things.freeGroups[:n-1]
+	} else {
+		if len(things.listGroups) == 0 {
+			things.listGroups = append(things.listGroups, listGroup{}) // 0 is no group.
+		}
+		id = uint32(len(things.listGroups))
+		things.listGroups = append(things.listGroups, listGroup{})
+	}
+	things.listGroups[id] = listGroup{owner: owner, refs: 1}
+	return id
+}
+
+func (things *Things[Thing]) retainGroup(id uint32) {
+	things.listGroups[id].refs++
+}
+
+// releaseGroup drops a reference to the group, and frees it and the groups it was spliced into
+// once nothing points to them.
+func (things *Things[Thing]) releaseGroup(id uint32) {
+	for id != 0 {
+		group := &things.listGroups[id]
+		group.refs--
+		if group.refs > 0 {
+			return
+		}
+		parent := group.parent
+		*group = listGroup{}
+		things.freeGroups = append(things.freeGroups, id)
+		id = parent
+	}
+}
+
+// rootGroup follows the Splices of the group to the List its members are inside of now.
+// It points every group on the way straight at it, so the next lookup is O(1).
+func (things *Things[Thing]) rootGroup(id uint32) uint32 {
+	parent := things.listGroups[id].parent
+	if parent == 0 {
+		return id
+	}
+	root := things.rootGroup(parent)
+	if root != parent {
+		things.retainGroup(root)
+		things.listGroups[id].parent = root
+		things.releaseGroup(parent)
+	}
+	return root
+}
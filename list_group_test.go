@@ -0,0 +1,131 @@
+package ts
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+type groupThing struct {
+	Items List[groupThing]
+}
+
+// checkGroups counts the List fields and groups that point to every group, and compares them with refs.
+func checkGroups(t *testing.T, things *Things[groupThing]) {
+	t.Helper()
+	refs := make([]uint32, max(len(things.listGroups), 1))
+	for idx := uint32(1); idx < uint32(len(things.used)); idx++ {
+		if things.used[idx] {
+			refs[things.at(idx).Items.group]++
+		}
+	}
+	for _, group := range things.listGroups {
+		refs[group.parent]++
+	}
+	refs[0] = 0 // 0 is no group.
+	free := make(map[uint32]bool)
+	for _, id := range things.freeGroups {
+		free[id] = true
+	}
+	for id := 1; id < len(refs); id++ {
+		if free[uint32(id)] != (refs[id] == 0) || things.listGroups[id].refs != refs[id] {
+			t.Fatalf("group %v has %v refs, counted %v, free %v", id, things.listGroups[id].refs, refs[id], free[uint32(id)])
+		}
+	}
+}
+
+func TestSpliceForwardsGroup(t *testing.T) {
+	things := NewThings[groupThing](16)
+	a, b, c := things.New(groupThing{}), things.New(groupThing{}), things.New(groupThing{})
+	for _, owner := range []ThingRef{a, b, c} {
+		things.Get(owner).Items.Init(owner, things)
+	}
+	item := things.New(groupThing{})
+	things.Get(a).Items.Append(item, things.New(groupThing{}))
+	moved := things.Get(item).Items.group
+
+	things.Get(a).Items.Splice(&things.Get(b).Items)
+	things.Get(b).Items.Splice(&things.Get(c).Items)
+	if things.Get(item).Items.group != moved {
+		t.Fatal("expected Splice to leave the members alone")
+	}
+	if owner := things.Get(item).Items.Owner(); owner != c {
+		t.Fatalf("expected moved Thing to be owned by %v, got %v", c, owner)
+	}
+	if !things.Get(c).Items.Contains(item) || things.Get(a).Items.Contains(item) || things.Get(c).Items.Len() != 2 {
+		t.Fatal("expected moved Things to be inside the List they were spliced into")
+	}
+	// the owners that spliced their Things away start over with an empty List.
+	things.Get(a).Items.Append(things.New(groupThing{}))
+	if things.Get(a).Items.Len() != 1 || things.Get(a).Items.Contains(item) {
+		t.Fatal("expected new Things to join the List of a, not the one it spliced into")
+	}
+	checkGroups(t, things)
+
+	for ref := range things.Each() {
+		things.Delete(ref)
+	}
+	if len(things.freeGroups) != len(things.listGroups)-1 {
+		t.Fatalf("expected every group to be freed, %v of %v are", len(things.freeGroups), len(things.listGroups)-1)
+	}
+}
+
+func TestListGroupsRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	things := NewThings[groupThing](32)
+	var refs []ThingRef
+	for step := range 20000 {
+		pick := func() ThingRef {
+			if len(refs) == 0 {
+				return nilRef
+			}
+			return refs[rng.IntN(len(refs))]
+		}
+		switch op := rng.IntN(7); {
+		case op == 0 && len(refs) < 24:
+			refs = append(refs, things.New(groupThing{}))
+		case op == 1:
+			if ref := pick(); things.IsNotNil(ref) && !things.Get(ref).Items.IsLinked() {
+				things.Get(ref).Items.Init(ref, things, OwnerPolicy(rng.IntN(2)))
+			}
+		case op == 2:
+			owner, ref := pick(), pick()
+			if things.IsNotNil(owner) && things.IsNotNil(ref) && things.Get(owner).Items.isInitialized {
+				if list := &things.Get(ref).Items; !list.inList() && (!list.isInitialized || ref == owner) {
+					things.Get(owner).Items.Append(ref)
+				}
+			}
+		case op == 3:
+			if ref := pick(); things.IsNotNil(ref) && things.Get(ref).Items.inList() {
+				things.Get(ref).Items.PopSelf()
+			}
+		case op == 4:
+			from, to := pick(), pick()
+			if things.IsNotNil(from) && things.IsNotNil(to) && things.Get(from).Items.isInitialized && things.Get(to).Items.isInitialized {
+				things.Get(from).Items.Splice(&things.Get(to).Items)
+			}
+		case op == 5:
+			if ref := pick(); things.IsNotNil(ref) {
+				things.Delete(ref)
+			}
+		default:
+			if ref := pick(); things.IsNotNil(ref) && things.Get(ref).Items.inList() {
+				// looking up the owner compresses the path of the group.
+				things.Get(ref).Items.Owner()
+			}
+		}
+		live := refs[:0]
+		for _, ref := range refs {
+			if things.IsNotNil(ref) {
+				live = append(live, ref)
+			}
+		}
+		refs = live
+		// validate looks up every owner, so it is not called every step to let Splices pile up.
+		if step%64 == 0 {
+			if broken := things.validate(); broken != 0 {
+				t.Fatalf("step %v: %v broken Lists", step, broken)
+			}
+		}
+		checkGroups(t, things)
+	}
+}
@@ -0,0 +1,95 @@
+package ts
+
+// Sort sorts the List in place, so that less(a, b) is true for every a before b.
+// Things that are equal keep their order (stable).
+//
+// It is a bottom-up merge sort that only relinks the Things, so it does not allocate.
+// less must not modify the List.
+//
+//	things.Get(Plr).Inventory.Sort(func(a, b *Thing) bool { return a.ItemID < b.ItemID })
+func (curr *List[Thing]) Sort(less func(a, b *Thing) bool) {
+	if !curr.isHead("Sort", 0) || curr.first == nilRef {
+		return
+	}
+	node := curr.getListDataFromThing
+
+	// break the circle, the list ends at nilRef while sorting.
+	node(node(curr.first).prev).next = nilRef
+
+	list := curr.first
+	for runSize := 1; ; runSize *= 2 {
+		p := list
+		list = nilRef
+		var tail ThingRef
+		merges := 0
+		for p != nilRef {
+			merges++
+			// q starts runSize Things after p
+			q := p
+			pSize := 0
+			for pSize < runSize && q != nilRef {
+				pSize++
+				q = node(q).next
+			}
+			qSize := runSize
+			// merge the runs starting at p and q
+			for pSize > 0 || (qSize > 0 && q != nilRef) {
+				var take ThingRef
+				switch {
+				case pSize == 0:
+					take, q, qSize = q, node(q).next, qSize-1
+				case qSize == 0 || q == nilRef:
+					take, p, pSize = p, node(p).next, pSize-1
+				case less(curr.things.get(q), curr.things.get(p)):
+					take, q, qSize = q, node(q).next, qSize-1
+				default: // equal Things take p first, this keeps the sort stable.
+					take, p, pSize = p, node(p).next, pSize-1
+				}
+				if tail == nilRef {
+					list = take
+				} else {
+					node(tail).next = take
+				}
+				tail = take
+			}
+			p = q
+		}
+		node(tail).next = nilRef
+		if merges <= 1 {
+			break
+		}
+	}
+
+	// fix the prev links and close the circle again.
+	curr.first = list
+	prev := list
+	for ref := node(list).next; ref != nilRef; ref = node(ref).next {
+		node(ref).prev = prev
+		prev = ref
+	}
+	node(list).prev = prev
+	node(prev).next = list
+}
+
+// InsertSorted inserts the Thing into a List that is sorted by less, keeping it sorted.
+// It goes after the Things it is equal to.
+// It does not do anything if the List is uninitialized.
+func (curr *List[Thing]) InsertSorted(newThingRef ThingRef, less func(a, b *Thing) bool) {
+	if !curr.isHead("InsertSorted into", 0) || !curr.canLink(newThingRef, 0) {
+		return
+	}
+	curr.adopt(newThingRef)
+	newThing := curr.things.get(newThingRef)
+	for ref, thing := range curr.Each() {
+		if !less(newThing, thing) {
+			continue
+		}
+		// insert before the first Thing that is greater.
+		curr.linkAfter(curr.getListDataFromThing(ref).prev, newThingRef)
+		if ref == curr.first {
+			curr.first = newThingRef
+		}
+		return
+	}
+	curr.linkLast(newThingRef)
+}
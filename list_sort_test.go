@@ -0,0 +1,104 @@
+package ts_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+func byItemID(a, b *Thing) bool { return a.ItemID < b.ItemID }
+
+func TestListSortStable(t *testing.T) {
+	things := ts.NewThings[Thing](256)
+	plr := things.New(Thing{Kind: KindPlayer})
+	things.Get(plr).Inventory.Init(plr, things)
+
+	rng := rand.New(rand.NewPCG(3, 4))
+	var want []Thing
+	for i := range 100 {
+		// few distinct ItemIDs, so there are many equal Things. Health remembers insertion order.
+		th := Thing{Kind: KindItem, ItemID: rng.Int32N(10), Health: int32(i)}
+		want = append(want, th)
+		things.Get(plr).Inventory.Append(things.New(th))
+	}
+	slices.SortStableFunc(want, func(a, b Thing) int { return int(a.ItemID - b.ItemID) })
+
+	inv := &things.Get(plr).Inventory
+	inv.Sort(byItemID)
+
+	i := 0
+	for _, th := range inv.Each() {
+		if th.ItemID != want[i].ItemID || th.Health != want[i].Health {
+			t.Fatalf("at %d: expected ItemID %d Health %d, got ItemID %d Health %d", i, want[i].ItemID, want[i].Health, th.ItemID, th.Health)
+		}
+		i++
+	}
+	if i != len(want) || inv.Len() != len(want) {
+		t.Fatalf("expected %d items after Sort, walked %d (Len %d)", len(want), i, inv.Len())
+	}
+	// the circle is intact in both directions
+	var backward []int32
+	for _, th := range inv.Backward() {
+		backward = append(backward, th.Health)
+	}
+	if len(backward) != len(want) || backward[0] != want[len(want)-1].Health {
+		t.Fatalf("expected Backward to start at the last sorted item, got %v", backward[:1])
+	}
+}
+
+func TestListSortSmall(t *testing.T) {
+	things, plr, _ := newInventory(ts.OrphanMembers) // 0, 1, 2
+	inv := &things.Get(plr).Inventory
+
+	inv.Sort(func(a, b *Thing) bool { return a.ItemID > b.ItemID })
+	if got, want := itemIDs(things, plr), []int32{2, 1, 0}; !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	inv.Clear()
+	inv.Sort(byItemID) // empty list
+	single := things.New(Thing{ItemID: 5})
+	inv.Append(single)
+	inv.Sort(byItemID)
+	if got, want := itemIDs(things, plr), []int32{5}; !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestListInsertSorted(t *testing.T) {
+	things := ts.NewThings[Thing](16)
+	plr := things.New(Thing{Kind: KindPlayer, ItemID: 4})
+	things.Get(plr).Inventory.Init(plr, things)
+	inv := &things.Get(plr).Inventory
+
+	for _, id := range []int32{5, 1, 3, 7, 3, 0} {
+		inv.InsertSorted(things.New(Thing{Kind: KindItem, ItemID: id}), byItemID)
+	}
+	inv.InsertSorted(plr, byItemID) // the owner can join its own list too
+	if got, want := itemIDs(things, plr), []int32{0, 1, 3, 3, 4, 5, 7}; !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if inv.Len() != 7 || inv.Last().ItemID != 7 {
+		t.Fatalf("expected Len 7 and Last 7, got %d and %d", inv.Len(), inv.Last().ItemID)
+	}
+}
+
+func TestListSortDoesNotAllocate(t *testing.T) {
+	things := ts.NewThings[Thing](64)
+	plr := things.New(Thing{Kind: KindPlayer})
+	things.Get(plr).Inventory.Init(plr, things)
+	for i := range 50 {
+		things.Get(plr).Inventory.Append(things.New(Thing{ItemID: int32(i * 7 % 50)}))
+	}
+	inv := &things.Get(plr).Inventory
+	flip := false
+	allocs := testing.AllocsPerRun(10, func() {
+		flip = !flip
+		inv.Sort(func(a, b *Thing) bool { return (a.ItemID < b.ItemID) == flip })
+	})
+	if allocs != 0 {
+		t.Fatalf("expected Sort to not allocate, got %v allocs", allocs)
+	}
+}
@@ -2,9 +2,12 @@
 package ts_test
 
 import (
+	"math/rand/v2"
+	"slices"
 	"testing"
 
 	ts "github.com/BrownNPC/thing-system"
+	"github.com/BrownNPC/thing-system/tstest"
 )
 
 func TestListIterationEmpty(t *testing.T) {
@@ -23,6 +26,90 @@ func TestListIterationEmpty(t *testing.T) {
 	}
 }
 
+func TestPopSelfFirstMember(t *testing.T) {
+	things := ts.NewThings(1024, Thing{})
+	head := things.New(Thing{Kind: 5})
+	a := things.New(Thing{Kind: 5, ItemID: 1})
+	b := things.New(Thing{Kind: 5, ItemID: 2})
+	things.Get(head).Inventory.Init(head, things)
+	things.Get(head).Inventory.Append(a, b)
+
+	// the head must move to b, otherwise the list still starts at a.
+	things.Get(a).Inventory.PopSelf()
+	var ids []int32
+	for _, th := range things.Get(head).Inventory.Each() {
+		ids = append(ids, th.ItemID)
+	}
+	if len(ids) != 1 || ids[0] != 2 {
+		t.Fatalf("expected only item 2 after popping the first member, got %v", ids)
+	}
+}
+
+func TestPopSelfOwnerInsideOwnList(t *testing.T) {
+	things := ts.NewThings(1024, Thing{})
+	item1 := things.New(Thing{Kind: 2, ItemID: 1})
+	item2 := things.New(Thing{Kind: 2, ItemID: 2})
+	things.Get(item1).Inventory.Init(item1, things)
+	things.Get(item1).Inventory.Append(item1, item2)
+
+	// popping the owner from its own list must keep the list and the other members.
+	things.Get(item1).Inventory.PopSelf()
+	if n := things.Get(item1).Inventory.Count(); n != 1 {
+		t.Fatalf("expected 1 member after the owner popped itself, got %d", n)
+	}
+}
+
+func TestAppendThingAlreadyInList(t *testing.T) {
+	things := ts.NewThings(1024, Thing{})
+	head := things.New(Thing{Kind: 3})
+	other := things.New(Thing{Kind: 3})
+	a := things.New(Thing{Kind: 3})
+	things.Get(head).Inventory.Init(head, things)
+	things.Get(other).Inventory.Init(other, things)
+	things.Get(head).Inventory.Append(a)
+
+	things.Get(head).Inventory.Append(a)
+	things.Get(other).Inventory.Append(a)
+	things.Get(head).Inventory.InsertNext(a)
+	if n := things.Get(head).Inventory.Count(); n != 1 {
+		t.Fatalf("expected a Thing to be linked once, got %d members", n)
+	}
+	if n := things.Get(other).Inventory.Count(); n != 0 {
+		t.Fatalf("expected a Thing inside a list to be refused by another list, got %d members", n)
+	}
+}
+
+func TestInsertNextMisuse(t *testing.T) {
+	things := ts.NewThings(1024, Thing{})
+	head := things.New(Thing{Kind: 3})
+	loose := things.New(Thing{Kind: 3})
+	a := things.New(Thing{Kind: 3})
+
+	// InsertNext on a Thing outside of any list must not link a.
+	things.Get(loose).Inventory.InsertNext(a)
+	things.Get(head).Inventory.Init(head, things)
+	things.Get(head).Inventory.Append(a, ts.ThingRef{})
+	if n := things.Get(head).Inventory.Count(); n != 1 {
+		t.Fatalf("expected a to be appended after the refused InsertNext, got %d members", n)
+	}
+}
+
+func TestEachAfterDeletingLowSlots(t *testing.T) {
+	things := ts.NewThings(1024, Thing{})
+	first := things.New(Thing{})
+	things.New(Thing{})
+	things.New(Thing{})
+	things.Delete(first)
+
+	n := 0
+	for range things.Each() {
+		n++
+	}
+	if n != 2 {
+		t.Fatalf("expected Each to visit 2 Things after deleting the first, got %d", n)
+	}
+}
+
 func TestListSelfAsFirstElement(t *testing.T) {
 	things := ts.NewThings(1024, Thing{})
 	item1 := things.New(Thing{Kind: 2, ItemID: 1})
@@ -145,3 +232,408 @@ func TestPopSelfRemoveMiddle(t *testing.T) {
 		t.Fatalf("expected Count() == 2 after removing middle element, got %d", things.Get(head).Inventory.Count())
 	}
 }
+
+func newInventory(policy ts.OwnerPolicy) (things *ts.Things[Thing], plr ts.ThingRef, items []ts.ThingRef) {
+	things = ts.NewThings(1024, Thing{})
+	plr = things.New(Thing{Kind: KindPlayer})
+	things.Get(plr).Inventory.Init(plr, things, policy)
+	for i := range 3 {
+		items = append(items, things.New(Thing{Kind: KindItem, ItemID: int32(i)}))
+	}
+	things.Get(plr).Inventory.Append(items...)
+	return things, plr, items
+}
+
+func TestDeleteOwnerOrphanMembers(t *testing.T) {
+	things, plr, items := newInventory(ts.OrphanMembers)
+	things.Delete(plr)
+
+	if things.IsNotNil(plr) {
+		t.Fatal("expected owner to be deleted")
+	}
+	for _, item := range items {
+		if !things.IsNotNil(item) {
+			t.Fatalf("expected %v to survive its owner", item)
+		}
+		// orphans can join a new list
+		if things.Get(item).Inventory.Owner() != (ts.ThingRef{}) {
+			t.Fatalf("expected %v to be unlinked", item)
+		}
+	}
+	chest := things.New(Thing{})
+	things.Get(chest).Inventory.Init(chest, things)
+	things.Get(chest).Inventory.Append(items...)
+	if n := things.Get(chest).Inventory.Count(); n != len(items) {
+		t.Fatalf("expected orphans to be appended to a new list, got %d", n)
+	}
+}
+
+func TestDeleteOwnerCascadeDelete(t *testing.T) {
+	things, plr, items := newInventory(ts.CascadeDelete)
+	// the owner is also inside its own list
+	things.Get(plr).Inventory.Append(plr)
+	things.Delete(plr)
+
+	if things.IsNotNil(plr) {
+		t.Fatal("expected owner to be deleted")
+	}
+	for _, item := range items {
+		if things.IsNotNil(item) {
+			t.Fatalf("expected %v to be deleted with its owner", item)
+		}
+	}
+	for ref := range things.Each() {
+		t.Fatalf("expected no Things left, found %v", ref)
+	}
+}
+
+// Linked has two List fields, so CascadeDelete can lead back to the Thing being deleted.
+type Linked struct {
+	A, B ts.List[Linked]
+}
+
+func TestDeleteCascadeCycle(t *testing.T) {
+	things := ts.NewThings[Linked](4)
+	things.SetReusePolicy(ts.ReuseFIFO)
+	x := things.New(Linked{})
+	y := things.New(Linked{})
+	things.Get(x).A.Init(x, things, ts.CascadeDelete)
+	things.Get(y).B.Init(y, things, ts.CascadeDelete)
+	things.Get(x).A.Append(y)
+	things.Get(y).B.Append(x)
+
+	things.Delete(x)
+	if things.IsNotNil(x) || things.IsNotNil(y) {
+		t.Fatal("expected both Things of the cycle to be deleted")
+	}
+	stats := things.Stats()
+	if stats.Live != 0 || stats.Deletes != 2 {
+		t.Fatalf("expected 0 live Things and 2 deletes, got %v and %v", stats.Live, stats.Deletes)
+	}
+	// every slot is freed once, so it is handed out once.
+	a, b := things.New(Linked{}), things.New(Linked{})
+	if a == b {
+		t.Fatalf("expected different refs, got %v twice", a)
+	}
+}
+
+func TestDeleteOwnerForbid(t *testing.T) {
+	things, plr, items := newInventory(ts.Forbid)
+	things.Delete(plr)
+
+	if !things.IsNotNil(plr) {
+		t.Fatal("expected deletion of owner with members to be refused")
+	}
+	if n := things.Get(plr).Inventory.Count(); n != len(items) {
+		t.Fatalf("expected list to be untouched, got %d items", n)
+	}
+
+	// once the list is empty, the owner can be deleted
+	for _, item := range items {
+		things.Get(item).Inventory.PopSelf()
+	}
+	things.Delete(plr)
+	if things.IsNotNil(plr) {
+		t.Fatal("expected owner of empty list to be deleted")
+	}
+}
+
+// itemIDs collects the ItemIDs of the list in iteration order.
+func itemIDs(things *ts.Things[Thing], owner ts.ThingRef) []int32 {
+	var ids []int32
+	for _, th := range things.Get(owner).Inventory.Each() {
+		ids = append(ids, th.ItemID)
+	}
+	return ids
+}
+
+func TestListReorder(t *testing.T) {
+	things, plr, items := newInventory(ts.OrphanMembers) // 0, 1, 2
+	extra := things.New(Thing{Kind: KindItem, ItemID: 3})
+	inv := &things.Get(plr).Inventory
+
+	steps := []struct {
+		name string
+		do   func()
+		want []int32
+	}{
+		{"Prepend", func() { inv.Prepend(extra) }, []int32{3, 0, 1, 2}},
+		{"MoveToBack", func() { things.Get(extra).Inventory.MoveToBack() }, []int32{0, 1, 2, 3}},
+		{"MoveToFront", func() { things.Get(items[2]).Inventory.MoveToFront() }, []int32{2, 0, 1, 3}},
+		{"MoveAfter", func() { things.Get(items[2]).Inventory.MoveAfter(extra) }, []int32{0, 1, 3, 2}},
+		{"Swap", func() { things.Get(items[0]).Inventory.Swap(items[2]) }, []int32{2, 1, 3, 0}},
+		{"Swap neighbors", func() { things.Get(items[1]).Inventory.Swap(extra) }, []int32{2, 3, 1, 0}},
+		{"Reverse", func() { inv.Reverse() }, []int32{0, 1, 3, 2}},
+		{"PopSelf", func() { things.Get(extra).Inventory.PopSelf() }, []int32{0, 1, 2}},
+		{"InsertBefore", func() { things.Get(items[0]).Inventory.InsertBefore(extra) }, []int32{3, 0, 1, 2}},
+	}
+	for _, step := range steps {
+		step.do()
+		if got := itemIDs(things, plr); !slices.Equal(got, step.want) {
+			t.Fatalf("%v: expected %v, got %v", step.name, step.want, got)
+		}
+		if last := inv.Last().ItemID; last != step.want[len(step.want)-1] {
+			t.Fatalf("%v: expected Last %v, got %v", step.name, step.want[len(step.want)-1], last)
+		}
+	}
+}
+
+func TestListContainsAndClear(t *testing.T) {
+	things, plr, items := newInventory(ts.OrphanMembers)
+	stranger := things.New(Thing{Kind: KindItem})
+	inv := &things.Get(plr).Inventory
+
+	if !inv.Contains(items[1]) || inv.Contains(stranger) || inv.Contains(plr) {
+		t.Fatal("Contains reported wrong membership")
+	}
+	// asking a member works too
+	if !things.Get(items[0]).Inventory.Contains(items[2]) {
+		t.Fatal("expected member to see its sibling")
+	}
+
+	inv.Clear()
+	if n := inv.Count(); n != 0 {
+		t.Fatalf("expected empty list after Clear, got %d", n)
+	}
+	for _, item := range items {
+		if !things.IsNotNil(item) || inv.Contains(item) {
+			t.Fatalf("expected %v to be alive and outside the list", item)
+		}
+	}
+}
+
+func TestListSplice(t *testing.T) {
+	things, plr, items := newInventory(ts.OrphanMembers)
+	chest := things.New(Thing{})
+	things.Get(chest).Inventory.Init(chest, things)
+	gold := things.New(Thing{Kind: KindItem, ItemID: 9})
+	things.Get(chest).Inventory.Append(gold)
+	// the owner stays behind when it is inside its own list
+	things.Get(plr).Inventory.Append(plr)
+
+	things.Get(plr).Inventory.Splice(&things.Get(chest).Inventory)
+
+	if got, want := itemIDs(things, chest), []int32{9, 0, 1, 2}; !slices.Equal(got, want) {
+		t.Fatalf("expected chest %v, got %v", want, got)
+	}
+	for _, item := range items {
+		if !things.Get(chest).Inventory.Contains(item) {
+			t.Fatalf("expected chest to contain %v", item)
+		}
+	}
+	if n := things.Get(plr).Inventory.Count(); n != 0 {
+		t.Fatalf("expected player inventory to be empty, got %d", n)
+	}
+	// deleting the old owner doesn't touch the moved Things
+	things.Delete(plr)
+	if n := things.Get(chest).Inventory.Count(); n != 4 {
+		t.Fatalf("expected chest to keep 4 items, got %d", n)
+	}
+}
+
+func TestListIsLinked(t *testing.T) {
+	things, plr, items := newInventory(ts.OrphanMembers)
+	stranger := things.New(Thing{Kind: KindItem})
+	if !things.Get(plr).Inventory.IsLinked() || !things.Get(items[0]).Inventory.IsLinked() {
+		t.Fatal("expected owner and member to be linked")
+	}
+	if things.Get(stranger).Inventory.IsLinked() {
+		t.Fatal("expected Thing outside of any list to not be linked")
+	}
+	things.Get(items[0]).Inventory.PopSelf()
+	if things.Get(items[0]).Inventory.IsLinked() {
+		t.Fatal("expected popped Thing to not be linked")
+	}
+}
+
+func TestListLenMatchesWalk(t *testing.T) {
+	h := tstest.Harness[Thing]{
+		Capacity: 16,
+		List:     func(t *Thing) *ts.List[Thing] { return &t.Inventory },
+		Policy:   ts.CascadeDelete,
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	data := make([]byte, 3*500)
+	for range 50 {
+		for i := range data {
+			data[i] = byte(rng.Uint32())
+		}
+		// the harness compares Len with Count and with the model after every operation.
+		h.Check(t, tstest.Decode(data))
+	}
+
+	things, plr, items := newInventory(ts.OrphanMembers)
+	things.Delete(items[1])
+	if n := things.Get(plr).Inventory.Len(); n != 2 {
+		t.Fatalf("expected Len 2 after deleting a member, got %d", n)
+	}
+}
+
+func TestListEachRemoveCurrent(t *testing.T) {
+	things, plr, items := newInventory(ts.OrphanMembers)
+	more := things.New(Thing{Kind: KindItem, ItemID: 3})
+	things.Get(plr).Inventory.Append(more) // 0, 1, 2, 3
+
+	var seen []int32
+	for ref, th := range things.Get(plr).Inventory.Each() {
+		seen = append(seen, th.ItemID)
+		switch th.ItemID {
+		case 0:
+			things.Get(ref).Inventory.PopSelf()
+		case 2:
+			things.Delete(ref)
+		}
+	}
+	if want := []int32{0, 1, 2, 3}; !slices.Equal(seen, want) {
+		t.Fatalf("expected to visit %v, got %v", want, seen)
+	}
+	if got, want := itemIDs(things, plr), []int32{1, 3}; !slices.Equal(got, want) {
+		t.Fatalf("expected remaining %v, got %v", want, got)
+	}
+	if things.IsNotNil(items[2]) || !things.IsNotNil(items[0]) {
+		t.Fatal("expected only the deleted item to be dead")
+	}
+
+	// deleting the owner mid-loop stops the loop without crashing
+	n := 0
+	for range things.Get(plr).Inventory.Each() {
+		n++
+		things.Delete(plr)
+	}
+	if n != 1 {
+		t.Fatalf("expected the loop to stop after the owner was deleted, got %d iterations", n)
+	}
+}
+
+func TestListRemoveWhere(t *testing.T) {
+	things, plr, items := newInventory(ts.OrphanMembers)
+	things.Get(plr).Inventory.Append(plr) // 0, 1, 2, owner
+
+	removed := things.Get(plr).Inventory.RemoveWhere(func(ref ts.ThingRef, th *Thing) bool {
+		return th.Kind == KindPlayer || th.ItemID != 1
+	})
+	if removed != 3 {
+		t.Fatalf("expected 3 removed, got %d", removed)
+	}
+	if got, want := itemIDs(things, plr), []int32{1}; !slices.Equal(got, want) {
+		t.Fatalf("expected remaining %v, got %v", want, got)
+	}
+	if things.Get(plr).Inventory.Len() != 1 || !things.IsNotNil(items[0]) {
+		t.Fatal("expected removed items to stay alive and Len to be updated")
+	}
+}
+
+func TestListBackwardAndEachFrom(t *testing.T) {
+	things, plr, items := newInventory(ts.OrphanMembers) // 0, 1, 2
+	inv := &things.Get(plr).Inventory
+
+	var got []int32
+	for _, th := range inv.Backward() {
+		got = append(got, th.ItemID)
+	}
+	if want := []int32{2, 1, 0}; !slices.Equal(got, want) {
+		t.Fatalf("Backward: expected %v, got %v", want, got)
+	}
+
+	got = got[:0]
+	for _, th := range inv.EachFrom(items[1]) {
+		got = append(got, th.ItemID)
+	}
+	if want := []int32{1, 2, 0}; !slices.Equal(got, want) {
+		t.Fatalf("EachFrom: expected %v, got %v", want, got)
+	}
+
+	// removing the current Thing while going backward
+	got = got[:0]
+	for ref, th := range inv.Backward() {
+		got = append(got, th.ItemID)
+		things.Get(ref).Inventory.PopSelf()
+	}
+	if want := []int32{2, 1, 0}; !slices.Equal(got, want) || inv.Len() != 0 {
+		t.Fatalf("Backward with PopSelf: expected %v and an empty list, got %v (Len %d)", want, got, inv.Len())
+	}
+
+	// starting from a Thing outside of the list yields nothing
+	for range inv.EachFrom(items[0]) {
+		t.Fatal("expected EachFrom a non-member to yield nothing")
+	}
+}
+
+func TestListEachFromPopSelf(t *testing.T) {
+	things, plr, items := newInventory(ts.OrphanMembers) // 0, 1, 2
+	inv := &things.Get(plr).Inventory
+	inv.Append(things.New(Thing{Kind: KindItem, ItemID: 3}))
+
+	var got []int32
+	for ref, th := range inv.EachFrom(items[1]) {
+		got = append(got, th.ItemID)
+		things.Get(ref).Inventory.PopSelf()
+	}
+	if want := []int32{1, 2, 3, 0}; !slices.Equal(got, want) || inv.Len() != 0 {
+		t.Fatalf("EachFrom with PopSelf: expected %v and an empty list, got %v (Len %d)", want, got, inv.Len())
+	}
+
+	inv.Append(items...)
+	got = got[:0]
+	for ref, th := range inv.EachFrom(items[2]) {
+		got = append(got, th.ItemID)
+		things.Delete(ref)
+	}
+	if want := []int32{2, 0, 1}; !slices.Equal(got, want) || inv.Len() != 0 {
+		t.Fatalf("EachFrom with Delete: expected %v and an empty list, got %v (Len %d)", want, got, inv.Len())
+	}
+}
+
+func TestListCycle(t *testing.T) {
+	things, plr, items := newInventory(ts.OrphanMembers) // 0, 1, 2
+	inv := &things.Get(plr).Inventory
+
+	// three rounds of turns, item 1 dies on its second turn
+	var turns []int32
+	turnsOf1 := 0
+	for ref, th := range inv.Cycle() {
+		turns = append(turns, th.ItemID)
+		if ref == items[1] {
+			turnsOf1++
+			if turnsOf1 == 2 {
+				things.Delete(ref)
+			}
+		}
+		if len(turns) == 8 {
+			break
+		}
+	}
+	if want := []int32{0, 1, 2, 0, 1, 2, 0, 2}; !slices.Equal(turns, want) {
+		t.Fatalf("expected turns %v, got %v", want, turns)
+	}
+
+	// the cycle ends once everyone is gone
+	n := 0
+	for ref := range inv.Cycle() {
+		things.Delete(ref)
+		n++
+	}
+	if n != 2 || inv.Len() != 0 {
+		t.Fatalf("expected the 2 remaining Things to get a final turn, got %d (Len %d)", n, inv.Len())
+	}
+}
+
+func TestListAppendDeleteDoesNotAllocate(t *testing.T) {
+	things, plr, _ := newInventory(ts.CascadeDelete)
+	chest := things.New(Thing{})
+	things.Get(chest).Inventory.Init(chest, things, ts.CascadeDelete)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		item := things.New(Thing{Kind: KindItem})
+		things.Get(plr).Inventory.Append(item)
+		bag := things.New(Thing{Kind: KindItem})
+		things.Get(bag).Inventory.Init(bag, things)
+		things.Get(chest).Inventory.Append(bag)
+		things.Delete(item, chest)
+		chest = things.New(Thing{})
+		things.Get(chest).Inventory.Init(chest, things, ts.CascadeDelete)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected append/delete cycles to not allocate, got %v allocs", allocs)
+	}
+}
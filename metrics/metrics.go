@@ -0,0 +1,182 @@
+// Package metrics serves the Stats of Things pools in the Prometheus text format,
+// so game servers can be scraped by Prometheus.
+//
+//	handler := metrics.NewHandler()
+//	handler.Register("things", things)
+//	handler.Register("particles", particles)
+//	http.Handle("/metrics", handler)
+//
+// Spawn and delete rates come from the counters, with rate(ts_things_created_total[1m]).
+// ts_warnings_total counts the misuse of every pool together, even after ts.SetLogger(nil).
+// It stays empty in release builds, which compile the warnings away.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+// Pool is a Things pool. *ts.Things[Thing] implements it for every Thing.
+type Pool interface {
+	PublishStats()
+	FrameStats() ts.Stats
+}
+
+// Handler is an http.Handler that writes the metrics of the registered pools.
+type Handler struct {
+	mu    sync.Mutex
+	names []string
+	pools []Pool
+}
+
+// NewHandler returns a Handler without pools.
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// Register adds the pool to the metrics under the name, which is used as the pool label.
+// The metrics are updated at every EndFrame of the pool.
+// Call it from the goroutine that uses the pool, because it calls PublishStats.
+func (handler *Handler) Register(name string, pool Pool) {
+	pool.PublishStats()
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	if i := slices.Index(handler.names, name); i >= 0 {
+		handler.pools[i] = pool
+		return
+	}
+	handler.names = append(handler.names, name)
+	handler.pools = append(handler.pools, pool)
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format, version 0.0.4.
+func (handler *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if r.Method == http.MethodHead {
+		return
+	}
+	handler.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (handler *Handler) WriteTo(w io.Writer) (int64, error) {
+	handler.mu.Lock()
+	names := slices.Clone(handler.names)
+	stats := make([]ts.Stats, len(handler.pools))
+	for i, pool := range handler.pools {
+		stats[i] = pool.FrameStats()
+	}
+	handler.mu.Unlock()
+
+	out := &writer{w: w}
+	gauge := func(name, help string, value func(ts.Stats) string) {
+		out.header(name, "gauge", help)
+		for i, stat := range stats {
+			out.sample(name, value(stat), "pool", names[i])
+		}
+	}
+	counter := func(name, help string, value func(ts.Stats) uint64) {
+		out.header(name, "counter", help)
+		for i, stat := range stats {
+			out.sample(name, strconv.FormatUint(value(stat), 10), "pool", names[i])
+		}
+	}
+
+	gauge("ts_things_live", "Number of live Things.", func(s ts.Stats) string { return formatUint(s.Live) })
+	gauge("ts_things_peak_live", "Highest number of live Things at once.", func(s ts.Stats) string { return formatUint(s.PeakLive) })
+	gauge("ts_things_capacity", "Number of Things that fit without growing.", func(s ts.Stats) string { return formatUint(s.Capacity) })
+	gauge("ts_things_max_capacity", "Number of Things the pool can grow to.", func(s ts.Stats) string { return formatUint(s.MaxCapacity) })
+	gauge("ts_things_free_slots", "Number of free slots.", func(s ts.Stats) string { return formatUint(s.FreeSlots) })
+	gauge("ts_things_fragmentation_ratio", "Share of free slots below the highest live Thing.", func(s ts.Stats) string {
+		return strconv.FormatFloat(s.Fragmentation, 'g', -1, 64)
+	})
+	gauge("ts_things_max_generation", "Highest number of times a slot was reused.", func(s ts.Stats) string {
+		return strconv.FormatUint(uint64(s.MaxGeneration), 10)
+	})
+	gauge("ts_things_memory_bytes", "Estimated memory used by the pool.", func(s ts.Stats) string {
+		return strconv.FormatUint(uint64(s.MemoryBytes), 10)
+	})
+	counter("ts_things_created_total", "Number of Things created.", func(s ts.Stats) uint64 { return s.News })
+	counter("ts_things_deleted_total", "Number of Things deleted.", func(s ts.Stats) uint64 { return s.Deletes })
+
+	out.header("ts_list_owners", "gauge", "Number of Things that own an initialized List in the field.")
+	for i, stat := range stats {
+		for _, list := range stat.Lists {
+			out.sample("ts_list_owners", strconv.Itoa(list.Lists), "pool", names[i], "field", list.Field)
+		}
+	}
+	out.header("ts_list_members", "gauge", "Number of Things inside a List in the field.")
+	for i, stat := range stats {
+		for _, list := range stat.Lists {
+			out.sample("ts_list_members", strconv.Itoa(list.Members), "pool", names[i], "field", list.Field)
+		}
+	}
+
+	// warnings are counted by the package, for every pool together, so they have no pool label.
+	warnings := ts.WarningCounts()
+	out.header("ts_warnings_total", "counter", "Number of misuse warnings and errors of all pools, by message, even when logging is off.")
+	for _, kind := range slices.Sorted(maps.Keys(warnings)) {
+		out.sample("ts_warnings_total", strconv.FormatUint(warnings[kind], 10), "kind", kind)
+	}
+	return out.n, out.err
+}
+
+func formatUint(v uint) string {
+	return strconv.FormatUint(uint64(v), 10)
+}
+
+// writer remembers the first error, so the metrics can be written without checking every line.
+type writer struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (out *writer) printf(format string, args ...any) {
+	if out.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(out.w, format, args...)
+	out.n += int64(n)
+	out.err = err
+}
+
+func (out *writer) header(name, kind, help string) {
+	out.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// sample writes one line of the metric, labels are pairs of names and values.
+func (out *writer) sample(name, value string, labels ...string) {
+	var b strings.Builder
+	b.WriteString(name)
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i == 0 {
+			b.WriteByte('{')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(labels[i])
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(labels[i+1]))
+		b.WriteByte('"')
+	}
+	if len(labels) > 0 {
+		b.WriteByte('}')
+	}
+	out.printf("%s %s\n", b.String(), value)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
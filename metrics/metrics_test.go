@@ -0,0 +1,108 @@
+package metrics_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+	"github.com/BrownNPC/thing-system/metrics"
+)
+
+func init() {
+	ts.SetLogger(nil)
+}
+
+type thing struct {
+	Value int
+	Items ts.List[thing]
+}
+
+func scrape(t *testing.T, handler http.Handler) string {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Fatalf("expected Prometheus text format, got Content-Type %q", got)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestHandler(t *testing.T) {
+	things := ts.NewThings[thing](8)
+	particles := ts.NewThings[thing](4)
+	handler := metrics.NewHandler()
+	handler.Register("things", things)
+	handler.Register(`odd"name`, particles)
+
+	owner := things.New(thing{})
+	items := ts.ListField(things, func(t *thing) *ts.List[thing] { return &t.Items })
+	items.Init(owner)
+	items.Get(owner).Append(things.New(thing{}))
+	things.Delete(things.New(thing{}))
+
+	// nothing changes until EndFrame.
+	if body := scrape(t, handler); !strings.Contains(body, `ts_things_live{pool="things"} 0`) {
+		t.Fatalf("expected metrics from before EndFrame, got\n%s", body)
+	}
+
+	things.EndFrame()
+	body := scrape(t, handler)
+	for _, want := range []string{
+		"# TYPE ts_things_live gauge",
+		`ts_things_live{pool="things"} 2`,
+		`ts_things_capacity{pool="things"} 8`,
+		`ts_things_capacity{pool="odd\"name"} 4`,
+		"# TYPE ts_things_created_total counter",
+		`ts_things_created_total{pool="things"} 3`,
+		`ts_things_deleted_total{pool="things"} 1`,
+		`ts_list_owners{pool="things",field="Items"} 1`,
+		`ts_list_members{pool="things",field="Items"} 1`,
+		"# TYPE ts_warnings_total counter",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in\n%s", want, body)
+		}
+	}
+}
+
+func TestHandlerWarnings(t *testing.T) {
+	if ts.BuildMode == "release" {
+		t.Skip("nothing is counted in release builds")
+	}
+	// logging is off, warnings are still counted.
+	ts.SetLogger(nil)
+	before := ts.WarningCounts()["Derefence of NilRef."]
+
+	things := ts.NewThings[thing](4)
+	handler := metrics.NewHandler()
+	handler.Register("things", things)
+	things.Get(ts.ThingRef{})
+	things.Get(ts.ThingRef{})
+
+	body := scrape(t, handler)
+	want := fmt.Sprintf(`ts_warnings_total{kind="Derefence of NilRef."} %v`, before+2)
+	if !strings.Contains(body, want) {
+		t.Fatalf("expected NilRef warnings to be counted, got\n%s", body)
+	}
+}
+
+func TestHandlerMethod(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	metrics.NewHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status %v, got %v", http.StatusMethodNotAllowed, recorder.Code)
+	}
+}
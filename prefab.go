@@ -0,0 +1,207 @@
+package ts
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Prefab is a template for spawning a Thing, and the Things linked into its Lists.
+type Prefab[Thing any] struct {
+	Thing Thing `json:"thing"`
+	// Children are spawned with the Thing and appended to its Lists, in order.
+	Children []PrefabChild `json:"children,omitempty"`
+}
+
+// PrefabChild is a Prefab that is spawned and appended to a List of its parent.
+type PrefabChild struct {
+	// List is the name given to the List field in Prefabs.AddList.
+	List string `json:"list"`
+	// Prefab is the name of the Prefab to spawn. It is looked up when the parent is spawned,
+	// so it can be registered after the parent.
+	Prefab string `json:"prefab"`
+}
+
+// Prefabs is a registry of named Prefabs that can be spawned into a pool.
+// Create it with NewPrefabs.
+//
+//	prefabs := ts.NewPrefabs(things)
+//	prefabs.AddList("Inventory", func(t *Thing) *ts.List[Thing] { return &t.Inventory })
+//	prefabs.Register("sword", ts.Prefab[Thing]{Thing: Thing{Kind: KindItem}})
+//	prefabs.Register("player", ts.Prefab[Thing]{
+//		Thing:    Thing{Kind: KindPlayer, Health: 100},
+//		Children: []ts.PrefabChild{{List: "Inventory", Prefab: "sword"}},
+//	})
+//	plr := prefabs.Spawn("player", func(t *Thing) { t.Position = Vector2{10, 10} })
+type Prefabs[Thing any] struct {
+	things  *Things[Thing]
+	lists   []prefabList[Thing]
+	prefabs map[string]Prefab[Thing]
+	// names of the Prefabs being spawned, to catch Prefabs that contain themselves.
+	spawning []string
+	// Things created by the running Spawns, deleted again if the pool fills up.
+	created []ThingRef
+}
+
+type prefabList[Thing any] struct {
+	name   string
+	lists  Lists[Thing]
+	policy []OwnerPolicy
+}
+
+// NewPrefabs creates an empty registry of Prefabs for the pool.
+func NewPrefabs[Thing any](things *Things[Thing]) *Prefabs[Thing] {
+	return &Prefabs[Thing]{things: things, prefabs: make(map[string]Prefab[Thing])}
+}
+
+// AddList registers a List field under a name, so Prefab children can be linked into it.
+// The List is initialized on spawned Things that have children in it, with the optional OwnerPolicy.
+//
+// Like with List.Init, a Thing that owns the List can't be a member of the same List field,
+// so a child can't have children in the List it is linked into.
+func (prefabs *Prefabs[Thing]) AddList(name string, field func(t *Thing) *List[Thing], policy_OPTIONAL ...OwnerPolicy) {
+	if prefabs.list(name) != nil {
+		if !releaseMode {
+			logWarn(0, "List is already added to Prefabs", "list", name)
+		}
+		return
+	}
+	lists := ListField(prefabs.things, field)
+	if !lists.valid {
+		return
+	}
+	prefabs.lists = append(prefabs.lists, prefabList[Thing]{name, lists, policy_OPTIONAL})
+}
+
+// Register adds a Prefab under a name, replacing the Prefab that had the name before.
+// Children that use a List that was not added with AddList are not registered.
+func (prefabs *Prefabs[Thing]) Register(name string, prefab Prefab[Thing]) {
+	if err := prefabs.check(prefab); err != nil {
+		if !releaseMode {
+			logError(0, "Tried to Register invalid Prefab", "prefab", name, "err", err)
+		}
+		return
+	}
+	prefabs.prefabs[name] = prefab
+}
+
+// LoadJSON registers the Prefabs of a JSON object of names to Prefabs:
+//
+//	{
+//		"sword":  {"thing": {"Kind": 2, "ItemID": 1}},
+//		"player": {
+//			"thing": {"Kind": 1, "Health": 100},
+//			"children": [{"list": "Inventory", "prefab": "sword"}]
+//		}
+//	}
+//
+// Things are decoded with encoding/json, so only exported fields of Thing are loaded.
+// Nothing is registered if there is an error.
+func (prefabs *Prefabs[Thing]) LoadJSON(r io.Reader) error {
+	var loaded map[string]Prefab[Thing]
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&loaded); err != nil {
+		return fmt.Errorf("ts: loading prefabs: %w", err)
+	}
+	// sorted, so the same file always reports the same error.
+	names := make([]string, 0, len(loaded))
+	for name := range loaded {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if err := prefabs.check(loaded[name]); err != nil {
+			return fmt.Errorf("ts: loading prefab %q: %w", name, err)
+		}
+	}
+	for _, name := range names {
+		prefabs.prefabs[name] = loaded[name]
+	}
+	return nil
+}
+
+// Spawn creates a Thing from the Prefab with the name, and spawns its children into its Lists.
+// overrides can change the new Thing before its children are linked, it can be nil.
+//
+// Spawn returns NilRef if the Prefab does not exist, or if the pool fills up before all the children are spawned.
+// Nothing is left in the pool then. Children whose Prefab does not exist are skipped with a warning.
+func (prefabs *Prefabs[Thing]) Spawn(name string, overrides func(t *Thing)) ThingRef {
+	start := len(prefabs.created)
+	defer func() { prefabs.created = prefabs.created[:start] }()
+	ref, full := prefabs.spawn(name, overrides, 0)
+	if full {
+		// children first, so Forbid owners are empty when they are deleted.
+		for _, created := range slices.Backward(prefabs.created[start:]) {
+			if prefabs.things.IsNotNil(created) {
+				prefabs.things.del(created)
+			}
+		}
+		return nilRef
+	}
+	return ref
+}
+
+// spawn returns full if the pool filled up, and the Things it created have to be deleted.
+func (prefabs *Prefabs[Thing]) spawn(name string, overrides func(t *Thing), skip int) (ref ThingRef, full bool) {
+	prefab, ok := prefabs.prefabs[name]
+	if !ok {
+		if !releaseMode {
+			logWarn(1+skip, "Tried to Spawn unknown Prefab", "prefab", name)
+		}
+		return nilRef, false
+	}
+	if slices.Contains(prefabs.spawning, name) {
+		if !releaseMode {
+			logWarn(1+skip, "Prefab contains itself, stopped spawning it", "prefab", name)
+		}
+		return nilRef, false
+	}
+	prefabs.spawning = append(prefabs.spawning, name)
+	defer func() { prefabs.spawning = prefabs.spawning[:len(prefabs.spawning)-1] }()
+
+	ref = prefabs.things.New(prefab.Thing)
+	if ref == nilRef {
+		return nilRef, true
+	}
+	prefabs.created = append(prefabs.created, ref)
+	if overrides != nil {
+		overrides(prefabs.things.get(ref))
+	}
+	for _, child := range prefab.Children {
+		list := prefabs.list(child.List)
+		if head := list.lists.Get(ref); !head.isInitialized {
+			head.init(ref, prefabs.things, list.lists.offset, list.policy, 1+skip)
+		}
+		childRef, full := prefabs.spawn(child.Prefab, nil, 1+skip)
+		if full {
+			return nilRef, true
+		}
+		if childRef == nilRef {
+			continue
+		}
+		list.lists.Get(ref).Append(childRef)
+	}
+	return ref, false
+}
+
+// check returns an error if the Prefab links children into a List that was not added.
+// The Prefabs of the children are not checked, they are looked up by Spawn.
+func (prefabs *Prefabs[Thing]) check(prefab Prefab[Thing]) error {
+	for i, child := range prefab.Children {
+		if prefabs.list(child.List) == nil {
+			return fmt.Errorf("children[%d]: unknown list %q, add it with Prefabs.AddList", i, child.List)
+		}
+	}
+	return nil
+}
+
+func (prefabs *Prefabs[Thing]) list(name string) *prefabList[Thing] {
+	for i := range prefabs.lists {
+		if prefabs.lists[i].name == name {
+			return &prefabs.lists[i]
+		}
+	}
+	return nil
+}
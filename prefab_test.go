@@ -0,0 +1,132 @@
+package ts_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+func newPrefabs(things *ts.Things[Thing]) *ts.Prefabs[Thing] {
+	prefabs := ts.NewPrefabs(things)
+	prefabs.AddList("Inventory", func(t *Thing) *ts.List[Thing] { return &t.Inventory }, ts.CascadeDelete)
+	return prefabs
+}
+
+func TestPrefabSpawn(t *testing.T) {
+	things := ts.NewThings[Thing](16)
+	prefabs := newPrefabs(things)
+	prefabs.Register("sword", ts.Prefab[Thing]{Thing: Thing{Kind: KindItem, ItemID: 1}})
+	prefabs.Register("potion", ts.Prefab[Thing]{Thing: Thing{Kind: KindItem, ItemID: 2}})
+	prefabs.Register("player", ts.Prefab[Thing]{
+		Thing: Thing{Kind: KindPlayer, Health: 100},
+		Children: []ts.PrefabChild{
+			{List: "Inventory", Prefab: "sword"},
+			{List: "Inventory", Prefab: "potion"},
+		},
+	})
+
+	plr := prefabs.Spawn("player", func(t *Thing) { t.Position = Vector2{10, 20} })
+	if got := things.Get(plr); got.Health != 100 || got.Position != (Vector2{10, 20}) {
+		t.Fatalf("expected template with overrides, got health %v position %v", got.Health, got.Position)
+	}
+	if got, want := itemIDs(things, plr), []int32{1, 2}; !slices.Equal(got, want) {
+		t.Fatalf("expected inventory %v, got %v", want, got)
+	}
+	var children []ts.ThingRef
+	for ref := range things.Get(plr).Inventory.Each() {
+		children = append(children, ref)
+	}
+	sword, potion := children[0], children[1]
+	// the OwnerPolicy was passed through.
+	things.Delete(plr)
+	if things.IsNotNil(sword) || things.IsNotNil(potion) {
+		t.Fatal("expected children to be deleted with the CascadeDelete policy")
+	}
+}
+
+func TestPrefabMisuse(t *testing.T) {
+	things := ts.NewThings[Thing](16)
+	prefabs := newPrefabs(things)
+	if ref := prefabs.Spawn("missing", nil); ref != (ts.ThingRef{}) {
+		t.Fatalf("expected NilRef for unknown Prefab, got %v", ref)
+	}
+
+	prefabs.Register("chest", ts.Prefab[Thing]{Children: []ts.PrefabChild{{List: "Loot", Prefab: "chest"}}})
+	if ref := prefabs.Spawn("chest", nil); ref != (ts.ThingRef{}) {
+		t.Fatalf("expected Prefab with unknown List to not be registered, got %v", ref)
+	}
+
+	// a Prefab that contains itself stops after one level.
+	prefabs.Register("box", ts.Prefab[Thing]{Children: []ts.PrefabChild{{List: "Inventory", Prefab: "box"}}})
+	box := prefabs.Spawn("box", nil)
+	if !things.IsNotNil(box) || things.Get(box).Inventory.Len() != 0 {
+		t.Fatal("expected recursive Prefab to spawn without children")
+	}
+	count := 0
+	for range things.Each() {
+		count++
+	}
+	if count != 1 {
+		t.Fatalf("expected only the box to be spawned, got %v Things", count)
+	}
+}
+
+func TestPrefabSpawnPoolFull(t *testing.T) {
+	things := ts.NewThings[Thing](2)
+	prefabs := ts.NewPrefabs(things)
+	prefabs.AddList("Inventory", func(t *Thing) *ts.List[Thing] { return &t.Inventory }, ts.Forbid)
+	prefabs.Register("player", ts.Prefab[Thing]{
+		Thing: Thing{Kind: KindPlayer},
+		Children: []ts.PrefabChild{
+			{List: "Inventory", Prefab: "sword"},
+			{List: "Inventory", Prefab: "sword"},
+		},
+	})
+	prefabs.Register("sword", ts.Prefab[Thing]{Thing: Thing{Kind: KindItem}})
+
+	if ref := prefabs.Spawn("player", nil); ref != (ts.ThingRef{}) {
+		t.Fatalf("expected NilRef when the pool fills up, got %v", ref)
+	}
+	if live := things.Stats().Live; live != 0 {
+		t.Fatalf("expected the Things of the failed Spawn to be deleted, %v are left", live)
+	}
+
+	// children are looked up when they are spawned, unknown ones are skipped.
+	prefabs.Register("chest", ts.Prefab[Thing]{Children: []ts.PrefabChild{{List: "Inventory", Prefab: "nope"}, {List: "Inventory", Prefab: "sword"}}})
+	chest := prefabs.Spawn("chest", nil)
+	if !things.IsNotNil(chest) || things.Get(chest).Inventory.Len() != 1 {
+		t.Fatal("expected unknown child Prefab to be skipped")
+	}
+}
+
+func TestPrefabLoadJSON(t *testing.T) {
+	things := ts.NewThings[Thing](16)
+	prefabs := newPrefabs(things)
+	err := prefabs.LoadJSON(strings.NewReader(`{
+		"sword":  {"thing": {"Kind": 2, "ItemID": 7}},
+		"player": {
+			"thing": {"Kind": 1, "Health": 50},
+			"children": [{"list": "Inventory", "prefab": "sword"}]
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plr := prefabs.Spawn("player", nil)
+	if things.Get(plr).Health != 50 {
+		t.Fatalf("expected Health from JSON, got %v", things.Get(plr).Health)
+	}
+	if got, want := itemIDs(things, plr), []int32{7}; !slices.Equal(got, want) {
+		t.Fatalf("expected inventory %v, got %v", want, got)
+	}
+
+	err = prefabs.LoadJSON(strings.NewReader(`{"bag": {"children": [{"list": "Pockets", "prefab": "sword"}]}}`))
+	if err == nil || !strings.Contains(err.Error(), `"bag"`) || !strings.Contains(err.Error(), `"Pockets"`) {
+		t.Fatalf("expected error naming the Prefab and List, got %v", err)
+	}
+	if err := prefabs.LoadJSON(strings.NewReader(`{"bag": {"thing": {"Helth": 1}}}`)); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+}
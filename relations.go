@@ -0,0 +1,257 @@
+package ts
+
+import (
+	"iter"
+	"slices"
+)
+
+// Relation is the type of an edge between two Things, like "allied with", "targets" or "is attached to".
+// Define your own constants:
+//
+//	const (
+//		AlliedWith ts.Relation = iota
+//		Targets
+//	)
+type Relation uint32
+
+// AnyRelation matches edges of every Relation in Out, In and the traversals.
+const AnyRelation Relation = ^Relation(0)
+
+// Relations stores typed directed edges between Things, for relationships that are not a single List.
+// Edges are removed automatically when either end is deleted.
+type Relations[Thing any] struct {
+	things *Things[Thing]
+	// edges of every slot, indexed by ThingRef.idx
+	out, in [][]edge
+
+	// used by the traversals to avoid allocating every frame.
+	visited    []uint32 // visitEpoch of the last traversal that visited the slot
+	visitEpoch uint32
+	traversing bool
+}
+
+type edge struct {
+	relation Relation
+	ref      ThingRef
+}
+
+// NewRelations creates Relations between the Things of the pool.
+func NewRelations[Thing any](things *Things[Thing]) *Relations[Thing] {
+	relations := &Relations[Thing]{things: things}
+	things.deleteHooks = append(things.deleteHooks, relations.removeAll)
+	return relations
+}
+
+// Add adds an edge of relation from one Thing to another.
+// Adding an edge that already exists does not do anything.
+func (relations *Relations[Thing]) Add(from ThingRef, relation Relation, to ThingRef) {
+	if !relations.things.IsNotNil(from) || !relations.things.IsNotNil(to) {
+		if !releaseMode {
+			logWarn(0, "Tried to Add relation with inactive Thing")
+		}
+		return
+	}
+	if relation == AnyRelation {
+		if !releaseMode {
+			logWarn(0, "Tried to Add edge of AnyRelation")
+		}
+		return
+	}
+	if relations.Has(from, relation, to) {
+		return
+	}
+	out := relations.edges(&relations.out, from.idx)
+	*out = append(*out, edge{relation, to})
+	in := relations.edges(&relations.in, to.idx)
+	*in = append(*in, edge{relation, from})
+}
+
+// Remove removes the edge of relation from one Thing to another.
+// Passing AnyRelation removes edges of every Relation between them.
+func (relations *Relations[Thing]) Remove(from ThingRef, relation Relation, to ThingRef) {
+	if !relations.things.IsNotNil(from) || !relations.things.IsNotNil(to) {
+		if !releaseMode {
+			logWarn(0, "Tried to Remove relation with inactive Thing")
+		}
+		return
+	}
+	removeEdges(relations.edges(&relations.out, from.idx), relation, to)
+	removeEdges(relations.edges(&relations.in, to.idx), relation, from)
+}
+
+// Has reports whether there is an edge of relation from one Thing to another.
+func (relations *Relations[Thing]) Has(from ThingRef, relation Relation, to ThingRef) bool {
+	if !relations.things.IsNotNil(from) {
+		return false
+	}
+	for _, e := range *relations.edges(&relations.out, from.idx) {
+		if e.ref == to && matches(e.relation, relation) {
+			return true
+		}
+	}
+	return false
+}
+
+// Out iterates over the Things that ref has an edge of relation to.
+func (relations *Relations[Thing]) Out(ref ThingRef, relation Relation) iter.Seq2[ThingRef, *Thing] {
+	return relations.neighbors(&relations.out, ref, relation)
+}
+
+// In iterates over the Things that have an edge of relation to ref.
+func (relations *Relations[Thing]) In(ref ThingRef, relation Relation) iter.Seq2[ThingRef, *Thing] {
+	return relations.neighbors(&relations.in, ref, relation)
+}
+
+// BreadthFirst iterates over every Thing reachable from ref by following edges of relation,
+// closest first. ref itself is not included.
+//
+// Traversals of the same Relations can not be nested.
+func (relations *Relations[Thing]) BreadthFirst(ref ThingRef, relation Relation) iter.Seq2[ThingRef, *Thing] {
+	return func(yield func(ThingRef, *Thing) bool) {
+		if !relations.startTraversal(ref) {
+			return
+		}
+		defer relations.endTraversal()
+
+		// Get queue from Pool.
+		queue, _ := relations.things.refPool.Get().([]ThingRef)
+		defer func() { relations.things.refPool.Put(queue[:0]) }()
+
+		queue = append(queue[:0], ref)
+		for i := 0; i < len(queue); i++ {
+			for _, e := range *relations.edges(&relations.out, queue[i].idx) {
+				if !matches(e.relation, relation) || !relations.visit(e.ref) {
+					continue
+				}
+				if !yield(e.ref, relations.things.get(e.ref)) {
+					return
+				}
+				queue = append(queue, e.ref)
+			}
+		}
+	}
+}
+
+// DepthFirst iterates over every Thing reachable from ref by following edges of relation,
+// going as deep as possible first. ref itself is not included.
+//
+// Traversals of the same Relations can not be nested.
+func (relations *Relations[Thing]) DepthFirst(ref ThingRef, relation Relation) iter.Seq2[ThingRef, *Thing] {
+	return func(yield func(ThingRef, *Thing) bool) {
+		if !relations.startTraversal(ref) {
+			return
+		}
+		defer relations.endTraversal()
+
+		// Get stack from Pool.
+		stack, _ := relations.things.refPool.Get().([]ThingRef)
+		defer func() { relations.things.refPool.Put(stack[:0]) }()
+
+		stack = stack[:0]
+		pushEdges := func(from ThingRef) {
+			// pushed in reverse, so edges are followed in the order they were added.
+			edges := *relations.edges(&relations.out, from.idx)
+			for i := len(edges) - 1; i >= 0; i-- {
+				if matches(edges[i].relation, relation) {
+					stack = append(stack, edges[i].ref)
+				}
+			}
+		}
+		pushEdges(ref)
+		for len(stack) > 0 {
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !relations.visit(current) {
+				continue
+			}
+			if !yield(current, relations.things.get(current)) {
+				return
+			}
+			pushEdges(current)
+		}
+	}
+}
+
+func (relations *Relations[Thing]) neighbors(edges *[][]edge, ref ThingRef, relation Relation) iter.Seq2[ThingRef, *Thing] {
+	return func(yield func(ThingRef, *Thing) bool) {
+		if !relations.things.IsNotNil(ref) {
+			return
+		}
+		for _, e := range *relations.edges(edges, ref.idx) {
+			if matches(e.relation, relation) && !yield(e.ref, relations.things.get(e.ref)) {
+				return
+			}
+		}
+	}
+}
+
+// startTraversal marks ref as visited. It returns false if the traversal can't start.
+func (relations *Relations[Thing]) startTraversal(ref ThingRef) bool {
+	if relations.traversing {
+		if !releaseMode {
+			logWarn(1, "Traversals of the same Relations can not be nested")
+		}
+		return false
+	}
+	if !relations.things.IsNotNil(ref) {
+		return false
+	}
+	relations.traversing = true
+	relations.visitEpoch++
+	if relations.visitEpoch == 0 { // wrapped around, old marks could collide
+		clear(relations.visited)
+		relations.visitEpoch = 1
+	}
+	relations.visit(ref)
+	return true
+}
+
+func (relations *Relations[Thing]) endTraversal() {
+	relations.traversing = false
+}
+
+// visit marks ref as visited, and returns false if it already was.
+func (relations *Relations[Thing]) visit(ref ThingRef) bool {
+	if int(ref.idx) >= len(relations.visited) {
+		relations.visited = append(relations.visited, make([]uint32, int(ref.idx)+1-len(relations.visited))...)
+	}
+	if relations.visited[ref.idx] == relations.visitEpoch {
+		return false
+	}
+	relations.visited[ref.idx] = relations.visitEpoch
+	return true
+}
+
+// edges returns the edges of the slot, growing the table if needed.
+func (relations *Relations[Thing]) edges(table *[][]edge, idx uint32) *[]edge {
+	if int(idx) >= len(*table) {
+		*table = append(*table, make([][]edge, int(idx)+1-len(*table))...)
+	}
+	return &(*table)[idx]
+}
+
+// removeAll removes every edge to and from the Thing. Called when it is deleted.
+func (relations *Relations[Thing]) removeAll(ref ThingRef) {
+	out := relations.edges(&relations.out, ref.idx)
+	for _, e := range *out {
+		removeEdges(relations.edges(&relations.in, e.ref.idx), e.relation, ref)
+	}
+	in := relations.edges(&relations.in, ref.idx)
+	for _, e := range *in {
+		removeEdges(relations.edges(&relations.out, e.ref.idx), e.relation, ref)
+	}
+	// keep the memory for the next Thing in this slot.
+	*out = (*out)[:0]
+	*in = (*in)[:0]
+}
+
+// removeEdges removes the edges of relation to ref.
+func removeEdges(edges *[]edge, relation Relation, ref ThingRef) {
+	*edges = slices.DeleteFunc(*edges, func(e edge) bool {
+		return e.ref == ref && matches(e.relation, relation)
+	})
+}
+
+func matches(relation, want Relation) bool {
+	return want == AnyRelation || relation == want
+}
@@ -0,0 +1,108 @@
+package ts_test
+
+import (
+	"slices"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+const (
+	AlliedWith ts.Relation = iota
+	Targets
+)
+
+func newNodes(t *testing.T, names ...string) (*ts.Things[Node], *ts.Relations[Node], map[string]ts.ThingRef) {
+	t.Helper()
+	things := ts.NewThings[Node](16)
+	refs := make(map[string]ts.ThingRef)
+	for _, name := range names {
+		refs[name] = things.New(Node{Name: name})
+	}
+	return things, ts.NewRelations(things), refs
+}
+
+func TestRelationsAddRemove(t *testing.T) {
+	_, relations, refs := newNodes(t, "a", "b", "c")
+	relations.Add(refs["a"], Targets, refs["b"])
+	relations.Add(refs["a"], Targets, refs["c"])
+	relations.Add(refs["a"], Targets, refs["b"]) // duplicate
+	relations.Add(refs["c"], Targets, refs["b"])
+	relations.Add(refs["a"], AlliedWith, refs["c"])
+
+	if got, want := names(relations.Out(refs["a"], Targets)), []string{"b", "c"}; !slices.Equal(got, want) {
+		t.Fatalf("expected a to target %v, got %v", want, got)
+	}
+	if got, want := names(relations.In(refs["b"], Targets)), []string{"a", "c"}; !slices.Equal(got, want) {
+		t.Fatalf("expected b to be targeted by %v, got %v", want, got)
+	}
+	if got, want := names(relations.Out(refs["a"], ts.AnyRelation)), []string{"b", "c", "c"}; !slices.Equal(got, want) {
+		t.Fatalf("expected all edges of a to be %v, got %v", want, got)
+	}
+	if !relations.Has(refs["a"], AlliedWith, refs["c"]) || relations.Has(refs["c"], AlliedWith, refs["a"]) {
+		t.Fatal("expected edges to be directed")
+	}
+
+	relations.Remove(refs["a"], Targets, refs["c"])
+	if relations.Has(refs["a"], Targets, refs["c"]) || !relations.Has(refs["a"], AlliedWith, refs["c"]) {
+		t.Fatal("expected Remove to only remove the edge of that Relation")
+	}
+	relations.Remove(refs["c"], ts.AnyRelation, refs["b"])
+	if got := names(relations.In(refs["b"], ts.AnyRelation)); !slices.Equal(got, []string{"a"}) {
+		t.Fatalf("expected only a to point at b, got %v", got)
+	}
+}
+
+func TestRelationsDeleteRemovesEdges(t *testing.T) {
+	things, relations, refs := newNodes(t, "a", "b", "c")
+	relations.Add(refs["a"], Targets, refs["b"])
+	relations.Add(refs["b"], Targets, refs["c"])
+	relations.Add(refs["c"], AlliedWith, refs["b"])
+
+	things.Delete(refs["b"])
+	if got := names(relations.Out(refs["a"], ts.AnyRelation)); len(got) != 0 {
+		t.Fatalf("expected edges to deleted Thing to be removed, got %v", got)
+	}
+	if got := names(relations.In(refs["c"], ts.AnyRelation)); len(got) != 0 {
+		t.Fatalf("expected edges from deleted Thing to be removed, got %v", got)
+	}
+
+	// the new Thing in the same slot starts without edges.
+	d := things.New(Node{Name: "d"})
+	if got := names(relations.Out(d, ts.AnyRelation)); len(got) != 0 {
+		t.Fatalf("expected reused slot to have no edges, got %v", got)
+	}
+	if relations.Has(refs["a"], Targets, d) {
+		t.Fatal("expected reused slot to not inherit edges")
+	}
+}
+
+func TestRelationsTraversal(t *testing.T) {
+	// a -> b -> d
+	// a -> c -> d -> a (cycle)
+	_, relations, refs := newNodes(t, "a", "b", "c", "d", "e")
+	relations.Add(refs["a"], Targets, refs["b"])
+	relations.Add(refs["a"], Targets, refs["c"])
+	relations.Add(refs["b"], Targets, refs["d"])
+	relations.Add(refs["c"], Targets, refs["d"])
+	relations.Add(refs["d"], Targets, refs["a"])
+	relations.Add(refs["d"], AlliedWith, refs["e"])
+
+	if got, want := names(relations.BreadthFirst(refs["a"], Targets)), []string{"b", "c", "d"}; !slices.Equal(got, want) {
+		t.Fatalf("expected breadth first %v, got %v", want, got)
+	}
+	if got, want := names(relations.DepthFirst(refs["a"], Targets)), []string{"b", "d", "c"}; !slices.Equal(got, want) {
+		t.Fatalf("expected depth first %v, got %v", want, got)
+	}
+	if got, want := names(relations.BreadthFirst(refs["a"], ts.AnyRelation)), []string{"b", "c", "d", "e"}; !slices.Equal(got, want) {
+		t.Fatalf("expected breadth first over any relation %v, got %v", want, got)
+	}
+
+	// stopping early ends the traversal, so another one can start.
+	for range relations.DepthFirst(refs["a"], Targets) {
+		break
+	}
+	if got := names(relations.BreadthFirst(refs["b"], Targets)); !slices.Equal(got, []string{"d", "a", "c"}) {
+		t.Fatalf("expected traversal after break to work, got %v", got)
+	}
+}
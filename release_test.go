@@ -0,0 +1,29 @@
+//go:build ts_release && !ts_debug
+
+package ts_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+func TestReleaseDoesNotLog(t *testing.T) {
+	var buf bytes.Buffer
+	ts.SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer ts.SetLogger(nil)
+
+	things := ts.NewThings(1, Thing{Health: 7})
+	dead := things.New(Thing{})
+	things.Delete(dead)
+	if got := things.Get(dead).Health; got != 7 {
+		t.Fatalf("expected NilRef to still return the Nil Thing, got %v", got)
+	}
+	things.Delete(dead)
+	things.Get(dead).Inventory.Append(dead)
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing to be logged in release builds, got %q", buf.String())
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"log/slog"
 	"os"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
+	"unsafe"
 
 	"github.com/lmittmann/tint"
 )
@@ -24,6 +27,28 @@ func (ref ThingRef) String() string {
 	return fmt.Sprintf("Thing(%v.%v)", ref.idx, ref.generation)
 }
 
+// ParseThingRef parses a ThingRef the way String prints it, like "Thing(3.1)",
+// or just its index and generation, like "3.1".
+func ParseThingRef(s string) (ThingRef, error) {
+	if s == "ThingRef(NIL)" {
+		return nilRef, nil
+	}
+	id := strings.TrimSuffix(strings.TrimPrefix(s, "Thing("), ")")
+	idx, generation, ok := strings.Cut(id, ".")
+	if !ok {
+		return nilRef, fmt.Errorf("ts: invalid ThingRef %q", s)
+	}
+	i, err := strconv.ParseUint(idx, 10, 32)
+	if err != nil {
+		return nilRef, fmt.Errorf("ts: invalid index in ThingRef %q: %w", s, err)
+	}
+	g, err := strconv.ParseUint(generation, 10, 32)
+	if err != nil {
+		return nilRef, fmt.Errorf("ts: invalid generation in ThingRef %q: %w", s, err)
+	}
+	return ThingRef{idx: uint32(i), generation: uint32(g)}, nil
+}
+
 var nilRef = ThingRef{}
 
 // Things is responsible for the creation, deletion, and reuse of a Thing.
@@ -31,13 +56,64 @@ var nilRef = ThingRef{}
 type Things[Thing any] struct {
 	maxThings    uint32
 	activeThings uint    //number of things that are active
-	things       []Thing // index 0 is nil (zero)
-	used         []bool
-	generations  []uint32
-	insideLists  map[ThingRef][]*List[Thing]
+	// storage for the Things, index 0 is nil (zero).
+	// Chunks are never moved, so pointers stay valid when the pool grows.
+	chunks      [][]Thing
+	chunkShift  uint32 // idx>>chunkShift is the chunk, 32 if there is only one.
+	chunkMask   uint32 // idx&chunkMask is the index inside of the chunk.
+	used        []bool
+	generations []uint32
+	// grows up to this many Things (+1 for nil). Same as maxThings if the pool can't grow.
+	limit  uint32
+	onGrow func(oldCapacity, newCapacity uint)
+	// what New does when the pool is full.
+	capacityPolicy CapacityPolicy
+	// refs in the order they were created, from agesStart. Only used by EvictOldest.
+	ages      []ThingRef
+	agesStart int
+	onEvict   func(ref ThingRef, thing *Thing)
+	// which free slot New uses.
+	reusePolicy ReusePolicy
+	quarantine  Quarantine
+	// free slots in the order they were freed, from freedStart. Not used by ReuseLowest.
+	freed      []freedSlot
+	freedStart int
+	frame      uint64 // number of EndFrame calls
+	deletions  uint64 // number of deleted Things
+	// only used in debug builds.
+	poison *poisonState
+	// returned by Get on NilRef, nil unless EnableNilSink was called.
+	sink      *Thing
+	sinkSites []uintptr
+	// where and at which frame every slot was last used by New, only if trackLeaks.
+	trackLeaks bool
+	leakSites  []uintptr
+	leakTicks  []uint64
+	// for Stats.
+	news       uint64
+	peakActive uint
+	// nil unless the Stats are published.
+	published *publishedStats
+	// offsets of the List and Tree fields inside of Thing.
+	// They are registered once per field by Init, and checked when a Thing is deleted.
+	listOffsets []uintptr
+	treeOffsets []uintptr
+	// the Lists that Things are inside of, and the unused entries. See listGroup.
+	listGroups []listGroup
+	freeGroups []uint32
+	// called with every Thing that is about to be deleted, while it is still active.
+	deleteHooks []func(ref ThingRef)
+	// slots that are inside of del, so a CascadeDelete cycle does not delete them twice.
+	deleting []bool
+	// nil unless EnableRefNulling was called.
+	refs *refIndex
+	// strong references of every slot, nil until the first Retain.
+	refCounts []uint32
 
 	// []*Thing
 	thingPointerPool sync.Pool
+	// []ThingRef
+	refPool sync.Pool
 }
 
 // NewThings allocates memory for all the Things upfront. It's also responsible for the creation, deletion, and reuse of a Thing.
@@ -57,14 +133,16 @@ func NewThings[Thing any](maxThings uint, nilThingState_OPTIONAL ...Thing) *Thin
 	maxThings += 1 // thing on index 0 is nil.
 	things :=&Things[Thing]{
 		maxThings:   uint32(maxThings),
-		things:      make([]Thing, maxThings),
+		chunks:      [][]Thing{make([]Thing, maxThings)},
+		chunkShift:  32,
+		chunkMask:   ^uint32(0),
 		used:        make([]bool, maxThings),
 		generations: make([]uint32, maxThings),
-		insideLists: make(map[ThingRef][]*List[Thing]),
+		limit:       uint32(maxThings),
 	}
 	// nil thing will be defaultStateOptional[0]
-	if len(nilThingState_OPTIONAL)>1{
-		things.things[0] = nilThingState_OPTIONAL[0]
+	if len(nilThingState_OPTIONAL)>0{
+		*things.at(0) = nilThingState_OPTIONAL[0]
 	}
 	return things
 }
@@ -74,8 +152,22 @@ func (things *Things[Thing]) New(thing Thing) ThingRef {
 	ref := things.findEmpty()
 	if ref != nilRef {
 		things.used[ref.idx] = true
-		things.things[ref.idx] = thing
+		*things.at(ref.idx) = thing
 		things.activeThings++
+		things.news++
+		things.peakActive = max(things.peakActive, things.activeThings)
+		if debugMode {
+			things.unpoisonSlot(ref.idx)
+		}
+		if things.trackLeaks {
+			things.recordNew(ref.idx)
+		}
+		if things.capacityPolicy == EvictOldest {
+			things.age(ref)
+		}
+		if things.refs != nil {
+			things.touch(ref.idx)
+		}
 	}
 	return ref
 }
@@ -88,27 +180,105 @@ func (things *Things[Thing]) Delete(ref ...ThingRef) {
 	}
 }
 
-func (things *Things[Thing]) del(ref ThingRef) {
-	if things.IsNotNil(ref) {
-		for _, list := range things.insideLists[ref] {
-			// if not already popped
-			if (*list != List[Thing]{}) {
-				list.PopSelf()
+// DeleteRecursive deletes the Things and all of their descendants in every Tree field.
+// Does not do anything if ref is Nil.
+func (things *Things[Thing]) DeleteRecursive(ref ...ThingRef) {
+	for _, ref := range ref {
+		if !things.IsNotNil(ref) {
+			if !releaseMode {
+				logWarn(0, "Tried to Delete inactive Thing")
+			}
+			continue
+		}
+		things.deleteRecursive(ref)
+	}
+}
+
+// deleteRecursive returns false if the deletion of the subtree was refused.
+func (things *Things[Thing]) deleteRecursive(ref ThingRef) bool {
+	for _, offset := range things.treeOffsets {
+		tree := fieldAt[Tree[Thing]](things.at(ref.idx), offset)
+		// deleting a child detaches it, so the next child becomes the first.
+		for tree.firstChild != nilRef {
+			if !things.deleteRecursive(tree.firstChild) {
+				return false
 			}
 		}
-		// free map memory
-		delete(things.insideLists, ref)
+	}
+	return things.del(ref)
+}
+
+// del returns false if the Thing was not deleted.
+func (things *Things[Thing]) del(ref ThingRef) bool {
+	if !things.IsNotNil(ref) {
+		if !releaseMode {
+			logWarn(1, "Tried to Delete inactive Thing")
+		}
+		return false
+	}
+	// already being deleted further up, by a CascadeDelete that leads back to it.
+	if int(ref.idx) < len(things.deleting) && things.deleting[ref.idx] {
+		return false
+	}
+	thing := things.at(ref.idx)
+	if things.forbidsDelete(thing) {
+		if !releaseMode {
+			logError(1, "Refused to Delete Thing that owns a List with members (Forbid policy). Empty the List first.")
+		}
+		return false
+	}
+
+	things.deleting = growTo(things.deleting, ref.idx)
+	things.deleting[ref.idx] = true
+
+	for _, hook := range things.deleteHooks {
+		hook(ref)
+	}
+
+	for _, offset := range things.listOffsets {
+		list := fieldAt[List[Thing]](thing, offset)
+		// pop from the list we are inside of
+		if list.inList() {
+			list.pop()
+		}
+		// and release the members of the list we own
+		if list.isInitialized {
+			list.releaseMembers()
+			things.releaseGroup(list.group)
+		}
+	}
+
+	for _, offset := range things.treeOffsets {
+		tree := fieldAt[Tree[Thing]](thing, offset)
+		tree.detach()
+		tree.orphanChildren()
+	}
+
+	things.deleting[ref.idx] = false
+	things.used[ref.idx] = false
+	things.generations[ref.idx] += 1
+	// zero it out  = things.things[0](set to nil)
+	*thing = *things.at(0)
+	if debugMode {
+		things.poisonSlot(ref.idx)
+	}
+	things.activeThings--
+	things.deletions++
+	if things.reusePolicy != ReuseLowest {
+		things.release(ref.idx)
+	}
+	return true
+}
 
-		things.used[ref.idx] = false
-		things.generations[ref.idx] += 1
-		// zero it out  = things.things[0](set to nil)
-		things.things[ref.idx] = things.things[0]
-		things.activeThings--
-	} else {
-		if logger != nil {
-			logger.Warn("Tried to Delete inactive Thing", "file", getParentCaller(0))
+// forbidsDelete reports whether the Thing owns a List with members that has the Forbid policy.
+func (things *Things[Thing]) forbidsDelete(thing *Thing) bool {
+	for _, offset := range things.listOffsets {
+		list := fieldAt[List[Thing]](thing, offset)
+		if list.isInitialized && list.policy == Forbid && list.hasOtherMembers() {
+			return true
 		}
 	}
+	return false
 }
 
 // Get  =t hings.things[0]returns a pointer to the Thing behind the ThingRef.
@@ -129,21 +299,54 @@ func (things *Things[Thing]) del(ref ThingRef) {
 //	someGlobalVariable.player.Health -= 1 // Unsafe
 func (things *Things[Thing]) Get(ref ThingRef) *Thing {
 	if things.IsNotNil(ref){
-		return &things.things[ref.idx]
+		if things.refs != nil {
+			things.touch(ref.idx)
+		}
+		if debugMode {
+			things.recordGet(ref.idx)
+		}
+		return things.at(ref.idx)
 	}
-	if logger != nil {
-		logger.Warn("Derefence of NilRef.", "file", getParentCaller(0))
+	if !releaseMode {
+		logWarn(0, "Derefence of NilRef.")
 	}
-	var z Thing = things.things[0]
+	if things.sink != nil {
+		things.recordNilGet()
+		return things.sink
+	}
+	var z Thing = *things.at(0)
 	return &z
 }
 
+// EndFrame tells the pool that a frame has ended. Call it once per frame.
+// It is used by ReuseQuarantine, EnableNilSink, TrackLeaks, EnableRefNulling and PublishExpvar.
+// In debug builds it also checks for poison like CheckPoison, and that every List is linked correctly.
+func (things *Things[Thing]) EndFrame() {
+	things.frame++
+	if things.sink != nil {
+		things.checkSink()
+	}
+	if things.refs != nil {
+		things.endFrameRefs()
+	}
+	if debugMode {
+		things.endFramePoison()
+		things.validate()
+	}
+	if things.published != nil {
+		things.updatePublishedStats()
+	}
+}
+
 // get is the same as Get but does not trigger a log.
 func (things *Things[Thing]) get(ref ThingRef) *Thing {
 	if things.isInBounds(ref) && things.isAlive(ref) {
-		return &things.things[ref.idx]
+		if things.refs != nil {
+			things.touch(ref.idx)
+		}
+		return things.at(ref.idx)
 	}
-	var z Thing = things.things[0]
+	var z Thing = *things.at(0)
 	return &z
 }
 
@@ -151,13 +354,21 @@ func (things *Things[Thing]) get(ref ThingRef) *Thing {
 //
 // The pointers should not be stored, only modified.
 func (things *Things[Thing]) Each() iter.Seq2[ThingRef, *Thing] {
+	return things.each(false)
+}
+
+// each iterates over all the active Things. If peek is true, they are read like Peek.
+func (things *Things[Thing]) each(peek bool) iter.Seq2[ThingRef, *Thing] {
 	return func(yield func(ThingRef, *Thing) bool) {
-		for id := uint(1); id <=things.activeThings; id++{
+		for id := 1; id < len(things.used); id++ {
 			if things.used[id]{
+				if !peek && things.refs != nil {
+					things.touch(uint32(id))
+				}
 				if !yield(
 					ThingRef{idx: uint32(id),
 						generation: things.generations[id]},
-					&things.things[id]) {
+					things.at(uint32(id))) {
 					break
 				}
 			}
@@ -214,13 +425,39 @@ func (things *Things[Thing]) isInBounds(ref ThingRef) bool {
 
 // findEmpty finds an unsed slot.
 func (things *Things[Thing]) findEmpty() ThingRef {
-	for i := 1; i < len(things.used); i++ {
-		if !things.used[i] {
-			return ThingRef{uint32(i), things.generations[i]}
+	if things.reusePolicy == ReuseLowest {
+		for i := 1; i < len(things.used); i++ {
+			if !things.used[i] {
+				return ThingRef{uint32(i), things.generations[i]}
+			}
+		}
+	} else if ref := things.takeFree(); ref != nilRef {
+		return ref
+	}
+	// the first slot of the new chunk.
+	if idx := things.maxThings; things.grow() {
+		if things.reusePolicy != ReuseLowest {
+			things.addFreeSlots(idx+1, things.maxThings)
+		}
+		return ThingRef{idx, things.generations[idx]}
+	}
+	if things.capacityPolicy == EvictOldest {
+		if ref := things.evictOldest(); ref != nilRef {
+			if things.reusePolicy != ReuseLowest {
+				// del freed it last, but it is used right away.
+				things.freed = things.freed[:len(things.freed)-1]
+			}
+			return ref
 		}
 	}
-	if logger != nil {
-		logger.Error("Ran out of memory, allocate more things in NewThings()", "file", getParentCaller(1))
+	if !releaseMode {
+		if quarantined := len(things.freed) - things.freedStart; things.reusePolicy == ReuseQuarantine && quarantined > 0 {
+			logError(1, "All the free slots are in quarantine, wait for it to pass or shorten the Quarantine in SetReusePolicy()", "quarantined", quarantined)
+		} else if things.chunkShift == 32 {
+			logError(1, "Ran out of memory, allocate more things in NewThings()")
+		} else {
+			logError(1, "Ran out of memory, the pool reached the maxThings passed to NewGrowableThings()")
+		}
 	}
 	return nilRef
 }
@@ -231,6 +468,21 @@ func (things *Things[Thing]) isAlive(ref ThingRef) bool {
 	return !dead
 }
 
+// registerOffset adds the offset of a List or Tree field, if it is not registered yet.
+func registerOffset(offsets []uintptr, offset uintptr) []uintptr {
+	for _, o := range offsets {
+		if o == offset {
+			return offsets
+		}
+	}
+	return append(offsets, offset)
+}
+
+// fieldAt returns a pointer to the field of type T at offset inside of thing.
+func fieldAt[T, Thing any](thing *Thing, offset uintptr) *T {
+	return (*T)(unsafe.Add(unsafe.Pointer(thing), offset))
+}
+
 // Returns line number of the function that called current function. useful for logging.
 func GetParentCaller() string {
 	return getParentCaller(1)
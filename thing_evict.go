@@ -0,0 +1,83 @@
+package ts
+
+// CapacityPolicy decides what New does when the pool is full.
+type CapacityPolicy uint8
+
+const (
+	// FailWhenFull makes New log an error and return NilRef. This is the default.
+	FailWhenFull CapacityPolicy = iota
+	// EvictOldest makes New delete the oldest active Thing and reuse its slot, like a ring buffer.
+	// Useful for particles and decals. Refs to the evicted Thing become stale like after any Delete.
+	EvictOldest
+)
+
+// SetCapacityPolicy sets what New does when the pool is full.
+// Growable pools only evict once they reach their maxThings.
+//
+// Things created before EvictOldest is set are treated as older than the ones after,
+// in slot order.
+func (things *Things[Thing]) SetCapacityPolicy(policy CapacityPolicy) {
+	if policy == things.capacityPolicy {
+		return
+	}
+	things.capacityPolicy = policy
+	things.ages = things.ages[:0]
+	things.agesStart = 0
+	if policy == EvictOldest {
+		for ref := range things.Each() {
+			things.ages = append(things.ages, ref)
+		}
+	}
+}
+
+// OnEvict sets a function that is called with every Thing that is evicted by New,
+// right before it is deleted.
+func (things *Things[Thing]) OnEvict(hook func(ref ThingRef, thing *Thing)) {
+	things.onEvict = hook
+}
+
+// evictOldest deletes the oldest Thing and returns its free slot.
+// It returns NilRef if every Thing refused to be deleted.
+func (things *Things[Thing]) evictOldest() ThingRef {
+	// refused Things go to the back, so each one is only tried once.
+	for tries := len(things.ages) - things.agesStart; tries > 0; tries-- {
+		oldest := things.ages[things.agesStart]
+		things.agesStart++
+		if !things.IsNotNil(oldest) { // deleted before
+			continue
+		}
+		// checked before OnEvict, so the hook only sees Things that are really evicted.
+		if !things.forbidsDelete(things.at(oldest.idx)) {
+			if things.onEvict != nil {
+				things.onEvict(oldest, things.get(oldest))
+			}
+			if things.del(oldest) {
+				return ThingRef{oldest.idx, things.generations[oldest.idx]}
+			}
+		}
+		things.ages = append(things.ages, oldest)
+	}
+	return nilRef
+}
+
+// age remembers when the Thing was created, for EvictOldest.
+func (things *Things[Thing]) age(ref ThingRef) {
+	// drop the refs that were already used up, so the queue does not grow forever.
+	if things.agesStart > len(things.ages)/2 {
+		n := copy(things.ages, things.ages[things.agesStart:])
+		things.ages = things.ages[:n]
+		things.agesStart = 0
+	}
+	// and the refs of Things that were deleted.
+	if len(things.ages) >= 2*len(things.used) {
+		alive := things.ages[:0]
+		for _, ref := range things.ages[things.agesStart:] {
+			if things.IsNotNil(ref) {
+				alive = append(alive, ref)
+			}
+		}
+		things.ages = alive
+		things.agesStart = 0
+	}
+	things.ages = append(things.ages, ref)
+}
@@ -0,0 +1,89 @@
+package ts_test
+
+import (
+	"slices"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+func TestEvictOldest(t *testing.T) {
+	things := ts.NewThings[Thing](3)
+	things.SetCapacityPolicy(ts.EvictOldest)
+	var evicted []int32
+	things.OnEvict(func(ref ts.ThingRef, thing *Thing) {
+		evicted = append(evicted, thing.ItemID)
+	})
+
+	a := things.New(Thing{ItemID: 1})
+	b := things.New(Thing{ItemID: 2})
+	c := things.New(Thing{ItemID: 3})
+	things.Delete(b)
+	b = things.New(Thing{ItemID: 4}) // reuses the slot of b, without evicting
+	if len(evicted) != 0 {
+		t.Fatalf("expected nothing to be evicted while there is room, got %v", evicted)
+	}
+
+	d := things.New(Thing{ItemID: 5})
+	e := things.New(Thing{ItemID: 6})
+	if want := []int32{1, 3}; !slices.Equal(evicted, want) {
+		t.Fatalf("expected oldest Things %v to be evicted, got %v", want, evicted)
+	}
+	// stale refs to evicted Things are detected, even though their slots are reused.
+	if things.IsNotNil(a) || things.IsNotNil(c) {
+		t.Fatal("expected refs to evicted Things to be stale")
+	}
+	if things.Get(a).ItemID != 0 {
+		t.Fatal("expected stale ref to return the Nil Thing")
+	}
+	for ref, want := range map[ts.ThingRef]int32{b: 4, d: 5, e: 6} {
+		if got := things.Get(ref).ItemID; got != want {
+			t.Fatalf("expected ItemID %v, got %v", want, got)
+		}
+	}
+}
+
+func TestEvictOldestRemovesFromList(t *testing.T) {
+	things := ts.NewThings[Thing](4)
+	things.SetCapacityPolicy(ts.EvictOldest)
+	item := things.New(Thing{Kind: KindItem, ItemID: 1})
+	plr := things.New(Thing{Kind: KindPlayer})
+	things.Get(plr).Inventory.Init(plr, things)
+	things.Get(plr).Inventory.Append(item, things.New(Thing{Kind: KindItem, ItemID: 2}))
+	things.New(Thing{})
+
+	things.New(Thing{}) // evicts the first item
+	if things.IsNotNil(item) {
+		t.Fatal("expected oldest item to be evicted")
+	}
+	if got, want := itemIDs(things, plr), []int32{2}; !slices.Equal(got, want) {
+		t.Fatalf("expected evicted item to be removed from the List, got %v", got)
+	}
+}
+
+func TestEvictOldestSkipsForbid(t *testing.T) {
+	things := ts.NewThings[Thing](2)
+	plr := things.New(Thing{Kind: KindPlayer})
+	things.Get(plr).Inventory.Init(plr, things, ts.Forbid)
+	item := things.New(Thing{Kind: KindItem})
+	things.Get(plr).Inventory.Append(item)
+	things.SetCapacityPolicy(ts.EvictOldest)
+	var evicted []ts.ThingRef
+	things.OnEvict(func(ref ts.ThingRef, thing *Thing) {
+		evicted = append(evicted, ref)
+	})
+
+	// plr refuses to be deleted, so the item goes.
+	if ref := things.New(Thing{}); ref == (ts.ThingRef{}) || things.IsNotNil(item) || !things.IsNotNil(plr) {
+		t.Fatal("expected the item to be evicted instead of the owner")
+	}
+	if !slices.Equal(evicted, []ts.ThingRef{item}) {
+		t.Fatalf("expected OnEvict to only be called with the item, got %v", evicted)
+	}
+	if ref := things.New(Thing{}); ref == (ts.ThingRef{}) || things.IsNotNil(plr) {
+		t.Fatal("expected the owner to be evicted once its List is empty")
+	}
+	if !slices.Equal(evicted, []ts.ThingRef{item, plr}) {
+		t.Fatalf("expected OnEvict to be called with the owner once it is evicted, got %v", evicted)
+	}
+}
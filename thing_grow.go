@@ -0,0 +1,70 @@
+package ts
+
+import "math/bits"
+
+// NewGrowableThings creates a pool that starts with one chunk of chunkSize Things,
+// and adds another chunk whenever it is full, until it holds maxThings.
+//
+// Chunks are never moved or freed, so pointers returned by Get stay valid while the pool grows
+// (they should still not be stored, see Get).
+// chunkSize is rounded up to a power of two.
+//
+//	things := ts.NewGrowableThings[Thing](256, 100_000)
+//	things.OnGrow(func(oldCapacity, newCapacity uint) {
+//		slog.Info("things grew", "capacity", newCapacity)
+//	})
+func NewGrowableThings[Thing any](chunkSize, maxThings uint, nilThingState_OPTIONAL ...Thing) *Things[Thing] {
+	chunkSize = max(chunkSize, 2)
+	shift := uint32(bits.Len(chunkSize - 1))
+	chunkSize = 1 << shift
+	limit := uint32(maxThings + 1) // thing on index 0 is nil.
+	first := min(uint32(chunkSize), limit)
+	things := &Things[Thing]{
+		maxThings:   first,
+		chunks:      [][]Thing{make([]Thing, first)},
+		chunkShift:  shift,
+		chunkMask:   uint32(chunkSize) - 1,
+		used:        make([]bool, first),
+		generations: make([]uint32, first),
+		limit:       limit,
+	}
+	if len(nilThingState_OPTIONAL) > 0 {
+		*things.at(0) = nilThingState_OPTIONAL[0]
+	}
+	return things
+}
+
+// OnGrow sets a function that is called every time the pool adds a chunk.
+// Capacities do not count the Nil Thing.
+func (things *Things[Thing]) OnGrow(hook func(oldCapacity, newCapacity uint)) {
+	things.onGrow = hook
+}
+
+// Capacity returns how many Things fit in the pool without growing.
+func (things *Things[Thing]) Capacity() uint {
+	return uint(things.maxThings) - 1
+}
+
+// at returns the storage of the slot.
+func (things *Things[Thing]) at(idx uint32) *Thing {
+	return &things.chunks[idx>>things.chunkShift][idx&things.chunkMask]
+}
+
+// grow adds a chunk. It returns false if the pool is at its limit.
+func (things *Things[Thing]) grow() bool {
+	if things.maxThings >= things.limit {
+		return false
+	}
+	chunkSize := things.chunkMask + 1
+	size := min(chunkSize, things.limit-things.maxThings)
+	// the last chunk can be smaller than the others, but it is never grown.
+	things.chunks = append(things.chunks, make([]Thing, size))
+	things.used = append(things.used, make([]bool, size)...)
+	things.generations = append(things.generations, make([]uint32, size)...)
+	oldCapacity := things.Capacity()
+	things.maxThings += size
+	if things.onGrow != nil {
+		things.onGrow(oldCapacity, things.Capacity())
+	}
+	return true
+}
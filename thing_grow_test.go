@@ -0,0 +1,64 @@
+package ts_test
+
+import (
+	"slices"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+func TestGrowableThings(t *testing.T) {
+	things := ts.NewGrowableThings[Thing](4, 10)
+	var events [][2]uint
+	things.OnGrow(func(oldCapacity, newCapacity uint) {
+		events = append(events, [2]uint{oldCapacity, newCapacity})
+	})
+	if got := things.Capacity(); got != 3 {
+		t.Fatalf("expected first chunk to hold 3 Things, got %v", got)
+	}
+
+	first := things.New(Thing{ItemID: 1})
+	firstPtr := things.Get(first)
+	var refs []ts.ThingRef
+	for i := range 9 {
+		refs = append(refs, things.New(Thing{ItemID: int32(i + 2)}))
+	}
+	if things.Get(first) != firstPtr || firstPtr.ItemID != 1 {
+		t.Fatal("expected Things to not move when the pool grows")
+	}
+	if want := [][2]uint{{3, 7}, {7, 10}}; !slices.Equal(events, want) {
+		t.Fatalf("expected grow events %v, got %v", want, events)
+	}
+	for i, ref := range refs {
+		if got := things.Get(ref).ItemID; got != int32(i+2) {
+			t.Fatalf("expected ItemID %v, got %v", i+2, got)
+		}
+	}
+
+	if ref := things.New(Thing{}); ref != (ts.ThingRef{}) {
+		t.Fatalf("expected NilRef when the pool is at maxThings, got %v", ref)
+	}
+	// freed slots are reused before growing.
+	things.Delete(refs[0])
+	if ref := things.New(Thing{}); ref == (ts.ThingRef{}) {
+		t.Fatal("expected freed slot to be reused")
+	}
+}
+
+func TestGrowableThingsLists(t *testing.T) {
+	things := ts.NewGrowableThings[Thing](2, 100)
+	plr := things.New(Thing{Kind: KindPlayer})
+	things.Get(plr).Inventory.Init(plr, things, ts.CascadeDelete)
+	var want []int32
+	for i := range int32(50) {
+		things.Get(plr).Inventory.Append(things.New(Thing{Kind: KindItem, ItemID: i}))
+		want = append(want, i)
+	}
+	if got := itemIDs(things, plr); !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	things.Delete(plr)
+	for range things.Each() {
+		t.Fatal("expected every Thing to be deleted with the owner")
+	}
+}
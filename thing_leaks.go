@@ -0,0 +1,95 @@
+package ts
+
+import (
+	"cmp"
+	"log/slog"
+	"runtime"
+	"slices"
+)
+
+// LeakGroup is a number of long-lived Things that were created at the same place.
+type LeakGroup struct {
+	// Site is where New was called, as file:line.
+	Site  string
+	Count int
+	// OldestTick is the tick the oldest of the Things was created at.
+	OldestTick uint64
+}
+
+// LogValue makes LeakGroup print nicely with slog.
+func (group LeakGroup) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("site", group.Site),
+		slog.Int("count", group.Count),
+		slog.Uint64("oldestTick", group.OldestTick),
+	)
+}
+
+// TrackLeaks makes New remember where and at which tick every Thing was created, for Leaks.
+// A tick is one EndFrame call.
+// Things created before TrackLeaks is called are not reported.
+func (things *Things[Thing]) TrackLeaks() {
+	things.trackLeaks = true
+}
+
+// Leaks groups the active Things that were created more than olderThan ticks ago by where they were created.
+// The largest groups come first.
+// filter can be nil, or return false for Things that are supposed to live long, like the player.
+//
+//	for _, leak := range things.Leaks(60*60, func(ref ts.ThingRef, t *Thing) bool { return t.Kind != KindPlayer }) {
+//		fmt.Println(leak.Count, "Things from", leak.Site)
+//	}
+func (things *Things[Thing]) Leaks(olderThan uint64, filter func(ref ThingRef, thing *Thing) bool) []LeakGroup {
+	groups := make(map[uintptr]*LeakGroup)
+	for idx := 1; idx < len(things.leakSites) && idx < len(things.used); idx++ {
+		pc := things.leakSites[idx]
+		if !things.used[idx] || pc == 0 {
+			continue
+		}
+		tick := things.leakTicks[idx]
+		if things.frame-tick <= olderThan {
+			continue
+		}
+		ref := ThingRef{uint32(idx), things.generations[idx]}
+		if filter != nil && !filter(ref, things.at(uint32(idx))) {
+			continue
+		}
+		group, ok := groups[pc]
+		if !ok {
+			group = &LeakGroup{Site: callSite(pc), OldestTick: tick}
+			groups[pc] = group
+		}
+		group.Count++
+		group.OldestTick = min(group.OldestTick, tick)
+	}
+
+	leaks := make([]LeakGroup, 0, len(groups))
+	for _, group := range groups {
+		leaks = append(leaks, *group)
+	}
+	slices.SortFunc(leaks, func(a, b LeakGroup) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Site, b.Site))
+	})
+	return leaks
+}
+
+// LogLeaks logs a warning for every group of Leaks, with the configured logger.
+func (things *Things[Thing]) LogLeaks(olderThan uint64, filter func(ref ThingRef, thing *Thing) bool) {
+	if releaseMode || logger == nil {
+		return
+	}
+	for _, leak := range things.Leaks(olderThan, filter) {
+		logger.Warn("Things might be leaking, they were not deleted for a long time", "leak", leak, "file", getParentCaller(0))
+	}
+}
+
+// recordNew remembers the caller of New and the current tick.
+func (things *Things[Thing]) recordNew(idx uint32) {
+	things.leakSites = growTo(things.leakSites, idx)
+	things.leakTicks = growTo(things.leakTicks, idx)
+	var pc [1]uintptr
+	// skip runtime.Callers, recordNew and New.
+	runtime.Callers(3, pc[:])
+	things.leakSites[idx] = pc[0]
+	things.leakTicks[idx] = things.frame
+}
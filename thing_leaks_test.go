@@ -0,0 +1,44 @@
+package ts_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+func TestLeaks(t *testing.T) {
+	things := ts.NewThings[Thing](16)
+	things.New(Thing{}) // not tracked
+	things.TrackLeaks()
+
+	things.New(Thing{Kind: KindPlayer})
+	var bullets []ts.ThingRef
+	var newSite string
+	for range 3 {
+		bullets = append(bullets, things.New(Thing{Kind: KindItem}))
+		_, file, line, _ := runtime.Caller(0)
+		newSite = fmt.Sprintf("%s:%d", filepath.Base(file), line-1)
+	}
+	things.EndFrame()
+	things.EndFrame()
+	things.New(Thing{Kind: KindItem}) // too young
+	things.Delete(bullets[0])
+
+	leaks := things.Leaks(1, func(ref ts.ThingRef, thing *Thing) bool { return thing.Kind != KindPlayer })
+	if len(leaks) != 1 {
+		t.Fatalf("expected one group of leaks, got %v", leaks)
+	}
+	if leak := leaks[0]; leak.Count != 2 || leak.OldestTick != 0 || !strings.Contains(leak.Site, newSite) {
+		t.Fatalf("expected 2 bullets from %v, got %+v", newSite, leak)
+	}
+
+	// without the filter, the player is its own group.
+	leaks = things.Leaks(1, nil)
+	if len(leaks) != 2 || leaks[0].Count != 2 || leaks[1].Count != 1 {
+		t.Fatalf("expected bullets then player, got %v", leaks)
+	}
+}
@@ -0,0 +1,26 @@
+package ts
+
+import "iter"
+
+// Peek returns the Thing for debugging tools that only read it, like the inspect package.
+// It returns a zero Thing and false if ref is not in use, without logging a warning.
+//
+// Unlike Get, the Thing is not marked for EnableRefNulling to scan again,
+// so ThingRefs written through the pointer are not nulled. Use Get to modify it.
+func (things *Things[Thing]) Peek(ref ThingRef) (*Thing, bool) {
+	if things.IsNotNil(ref) {
+		return things.at(ref.idx), true
+	}
+	var z Thing = *things.at(0)
+	return &z, false
+}
+
+// PeekEach iterates over all the active Things like Each, but reads them like Peek.
+func (things *Things[Thing]) PeekEach() iter.Seq2[ThingRef, *Thing] {
+	return things.each(true)
+}
+
+// PeekEach iterates over the List like Each, but reads the Things like Things.Peek.
+func (curr *List[Thing]) PeekEach() iter.Seq2[ThingRef, *Thing] {
+	return curr.each(true)
+}
@@ -0,0 +1,60 @@
+package ts
+
+import "testing"
+
+type peekThing struct {
+	Target ThingRef
+	Items  List[peekThing]
+}
+
+func TestPeekDoesNotTouch(t *testing.T) {
+	things := NewThings[peekThing](8)
+	things.EnableRefNulling()
+	owner := things.New(peekThing{})
+	things.Get(owner).Items.Init(owner, things)
+	things.Get(owner).Items.Append(things.New(peekThing{Target: owner}), things.New(peekThing{}))
+	things.EndFrame() // forgets everything touched so far.
+	if n := len(things.refs.dirtyList); n != 0 {
+		t.Fatalf("expected EndFrame to forget the touched Things, %v are left", n)
+	}
+
+	for range things.PeekEach() {
+	}
+	list, ok := things.Peek(owner)
+	if !ok {
+		t.Fatal("expected Peek to find the owner")
+	}
+	for range list.Items.PeekEach() {
+	}
+	list.Items.Len()
+	list.Items.Owner()
+	if n := len(things.refs.dirtyList); n != 0 {
+		t.Fatalf("expected reading with Peek to leave the Things alone, %v were touched", n)
+	}
+
+	if _, ok := things.Peek(ThingRef{idx: 7}); ok {
+		t.Fatal("expected Peek of an unused ref to return false")
+	}
+	things.Get(owner)
+	if n := len(things.refs.dirtyList); n != 1 {
+		t.Fatalf("expected Get to touch the Thing, %v were touched", n)
+	}
+}
+
+func TestParseThingRef(t *testing.T) {
+	things := NewThings[peekThing](8)
+	ref := things.New(peekThing{})
+	for _, s := range []string{ref.String(), "1.0"} {
+		if got, err := ParseThingRef(s); err != nil || got != ref {
+			t.Errorf("ParseThingRef(%q) = %v, %v, expected %v", s, got, err, ref)
+		}
+	}
+	if got, err := ParseThingRef(nilRef.String()); err != nil || got != nilRef {
+		t.Errorf("expected NilRef to parse, got %v, %v", got, err)
+	}
+	for _, s := range []string{"", "1", "1.x", "x.0", "Thing(1.0.0)", "-1.0", "4294967296.0"} {
+		if _, err := ParseThingRef(s); err == nil {
+			t.Errorf("expected ParseThingRef(%q) to fail", s)
+		}
+	}
+}
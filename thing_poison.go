@@ -0,0 +1,179 @@
+package ts
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+// poisonPattern is written over freed slots in debug builds. It shows up as 0xDEADBEEF in a debugger.
+var poisonPattern = [4]byte{0xEF, 0xBE, 0xAD, 0xDE}
+
+// PoisonViolation is a write to a deleted Thing, found by CheckPoison.
+type PoisonViolation struct {
+	// Ref is the deleted Thing that was written to.
+	Ref ThingRef
+	// Offset is the first byte inside of the Thing that was written to.
+	Offset uintptr
+	// GetSite is where Get was last called for the Thing before it was deleted,
+	// the pointer that was written through most likely came from there.
+	GetSite string
+}
+
+func (v PoisonViolation) String() string {
+	return fmt.Sprintf("%v was written to at offset %v after it was deleted, pointer from Get at %v", v.Ref, v.Offset, v.GetSite)
+}
+
+// poisonState is only used in debug builds.
+type poisonState struct {
+	// byte ranges of Thing that don't hold pointers, only those can be poisoned without confusing the GC.
+	ranges [][2]uintptr
+	// slots that hold poison.
+	poisoned []bool
+	// the caller of the last Get of every slot.
+	getSites []uintptr
+	// violations found by the last EndFrame, until CheckPoison returns them or the next EndFrame.
+	found []PoisonViolation
+}
+
+// CheckPoison reports writes to deleted Things through pointers that were kept after Get.
+// It logs an error for each one, and returns them.
+//
+// It only works in debug builds (go build -tags ts_debug), where deleted Things are poisoned.
+// Call it once per frame. In other builds it does not do anything.
+//
+// EndFrame checks for poison too in debug builds. The violations it found are returned
+// by the next CheckPoison before the following EndFrame, so it can be called after EndFrame.
+func (things *Things[Thing]) CheckPoison() []PoisonViolation {
+	if !debugMode || things.poison == nil {
+		return nil
+	}
+	violations := append(things.poison.found, things.scanPoison(1)...)
+	things.poison.found = nil
+	return violations
+}
+
+// endFramePoison checks for poison at EndFrame, and keeps the violations for CheckPoison.
+func (things *Things[Thing]) endFramePoison() {
+	if things.poison == nil {
+		return
+	}
+	things.poison.found = things.scanPoison(2)
+}
+
+// scanPoison logs and returns the new violations, with the caller skip frames above its caller.
+func (things *Things[Thing]) scanPoison(skip int) []PoisonViolation {
+	var violations []PoisonViolation
+	for idx, poisoned := range things.poison.poisoned {
+		if !poisoned {
+			continue
+		}
+		offset, ok := things.checkPoison(uint32(idx))
+		if ok {
+			continue
+		}
+		violation := PoisonViolation{
+			Ref:     ThingRef{uint32(idx), things.generations[idx] - 1},
+			Offset:  offset,
+			GetSite: callSite(things.poison.getSites[idx]),
+		}
+		if !releaseMode {
+			logError(skip, "Write to deleted Thing, a pointer from Get was stored", "ref", violation.Ref, "offset", offset, "get", violation.GetSite)
+		}
+		violations = append(violations, violation)
+		// report it only once.
+		things.poisonSlot(uint32(idx))
+	}
+	return violations
+}
+
+// poisonSlot fills the bytes of the freed slot that do not hold pointers with poisonPattern.
+func (things *Things[Thing]) poisonSlot(idx uint32) {
+	state := things.poisonState()
+	bytes := unsafe.Slice((*byte)(unsafe.Pointer(things.at(idx))), unsafe.Sizeof(*things.at(idx)))
+	for _, r := range state.ranges {
+		for i := r[0]; i < r[1]; i++ {
+			bytes[i] = poisonPattern[i%4]
+		}
+	}
+	state.poisoned = growTo(state.poisoned, idx)
+	state.poisoned[idx] = true
+}
+
+// checkPoison returns the offset of the first byte that is not poison anymore.
+func (things *Things[Thing]) checkPoison(idx uint32) (uintptr, bool) {
+	bytes := unsafe.Slice((*byte)(unsafe.Pointer(things.at(idx))), unsafe.Sizeof(*things.at(idx)))
+	for _, r := range things.poison.ranges {
+		for i := r[0]; i < r[1]; i++ {
+			if bytes[i] != poisonPattern[i%4] {
+				return i, false
+			}
+		}
+	}
+	return 0, true
+}
+
+// unpoisonSlot is called when the slot is used again.
+func (things *Things[Thing]) unpoisonSlot(idx uint32) {
+	if things.poison != nil && int(idx) < len(things.poison.poisoned) {
+		things.poison.poisoned[idx] = false
+	}
+}
+
+// recordGet remembers the caller of Get.
+func (things *Things[Thing]) recordGet(idx uint32) {
+	state := things.poisonState()
+	state.getSites = growTo(state.getSites, idx)
+	var pc [1]uintptr
+	// skip runtime.Callers, recordGet and Get.
+	runtime.Callers(3, pc[:])
+	state.getSites[idx] = pc[0]
+}
+
+func (things *Things[Thing]) poisonState() *poisonState {
+	if things.poison == nil {
+		things.poison = &poisonState{ranges: pointerFreeRanges(reflect.TypeFor[Thing](), 0, nil)}
+	}
+	return things.poison
+}
+
+// pointerFreeRanges appends the byte ranges inside of t that do not hold pointers, starting at base.
+func pointerFreeRanges(t reflect.Type, base uintptr, ranges [][2]uintptr) [][2]uintptr {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		if n := len(ranges); n > 0 && ranges[n-1][1] == base {
+			ranges[n-1][1] = base + t.Size()
+		} else {
+			ranges = append(ranges, [2]uintptr{base, base + t.Size()})
+		}
+	case reflect.Array:
+		for i := range t.Len() {
+			ranges = pointerFreeRanges(t.Elem(), base+uintptr(i)*t.Elem().Size(), ranges)
+		}
+	case reflect.Struct:
+		for i := range t.NumField() {
+			field := t.Field(i)
+			ranges = pointerFreeRanges(field.Type, base+field.Offset, ranges)
+		}
+	}
+	return ranges
+}
+
+// growTo makes s long enough to index idx.
+func growTo[T any](s []T, idx uint32) []T {
+	if int(idx) < len(s) {
+		return s
+	}
+	return append(s, make([]T, int(idx)+1-len(s))...)
+}
+
+func callSite(pc uintptr) string {
+	if pc == 0 {
+		return "unknown"
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return fmt.Sprintf("%v:%v", frame.File, frame.Line)
+}
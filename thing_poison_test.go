@@ -0,0 +1,69 @@
+//go:build ts_debug
+
+package ts_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+// Hero is a Thing with pointers, which are not poisoned.
+type Hero struct {
+	Name   string
+	Health int32
+	Items  []int
+	Stats  [4]float32
+}
+
+func TestCheckPoison(t *testing.T) {
+	things := ts.NewThings[Hero](4)
+	things.SetReusePolicy(ts.ReuseQuarantine, ts.Quarantine{Frames: 10})
+	ref := things.New(Hero{Name: "link", Health: 3, Items: []int{1}})
+	other := things.New(Hero{Name: "zelda"})
+
+	stored := things.Get(ref) // stored for later, this is the bug.
+	_, file, line, _ := runtime.Caller(0)
+	getSite := fmt.Sprintf("%s:%d", filepath.Base(file), line-1)
+	things.Get(other).Health = 10
+	things.Delete(ref, other)
+	if got := things.CheckPoison(); len(got) != 0 {
+		t.Fatalf("expected no violations right after Delete, got %v", got)
+	}
+
+	stored.Stats[2] = 1
+	violations := things.CheckPoison()
+	if len(violations) != 1 {
+		t.Fatalf("expected one violation, got %v", violations)
+	}
+	if v := violations[0]; v.Ref != ref || !strings.Contains(v.GetSite, getSite) {
+		t.Fatalf("expected violation of %v with the Get site %v, got %v", ref, getSite, v)
+	}
+	if got := things.CheckPoison(); len(got) != 0 {
+		t.Fatalf("expected violation to be reported once, got %v", got)
+	}
+
+	// the violations found by EndFrame are kept for CheckPoison.
+	stored.Health = 4
+	things.EndFrame()
+	if got := things.CheckPoison(); len(got) != 1 || got[0].Ref != ref {
+		t.Fatalf("expected the violation found by EndFrame, got %v", got)
+	}
+	if got := things.CheckPoison(); len(got) != 0 {
+		t.Fatalf("expected violation found by EndFrame to be reported once, got %v", got)
+	}
+
+	// reused slots are not checked.
+	for range 10 {
+		things.EndFrame()
+	}
+	reused := things.New(Hero{})
+	things.Get(reused).Health = 5
+	if got := things.CheckPoison(); len(got) != 0 {
+		t.Fatalf("expected reused slot to not be reported, got %v", got)
+	}
+}
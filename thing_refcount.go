@@ -0,0 +1,66 @@
+package ts
+
+// Retain adds a strong reference to the Thing.
+// A retained Thing is deleted automatically by the Release that drops its count to zero.
+//
+// Things start with a count of zero, and are not deleted until they are retained and released.
+// They can still be deleted with Delete while retained.
+//
+//	pattern := things.New(Thing{Kind: ProjectilePattern})
+//	things.Retain(pattern) // turret A
+//	things.Retain(pattern) // turret B
+//	things.Release(pattern)
+//	things.Release(pattern) // deleted
+func (things *Things[Thing]) Retain(ref ThingRef) {
+	if !things.IsNotNil(ref) {
+		if !releaseMode {
+			logWarn(0, "Tried to Retain inactive Thing")
+		}
+		return
+	}
+	if things.refCounts == nil {
+		things.refCounts = make([]uint32, len(things.used))
+		things.deleteHooks = append(things.deleteHooks, things.resetRefCount)
+	}
+	if int(ref.idx) >= len(things.refCounts) { // the pool grew
+		things.refCounts = append(things.refCounts, make([]uint32, len(things.used)-len(things.refCounts))...)
+	}
+	things.refCounts[ref.idx]++
+}
+
+// Release removes a strong reference to the Thing, and deletes it if it was the last one.
+// Releasing a stale ref, or a Thing that is not retained, logs a warning.
+func (things *Things[Thing]) Release(ref ThingRef) {
+	if !things.IsNotNil(ref) {
+		if !releaseMode {
+			logWarn(0, "Tried to Release stale or already freed Thing", "ref", ref)
+		}
+		return
+	}
+	if things.RefCount(ref) == 0 {
+		if !releaseMode {
+			logWarn(0, "Tried to Release Thing that is not retained", "ref", ref)
+		}
+		return
+	}
+	things.refCounts[ref.idx]--
+	if things.refCounts[ref.idx] == 0 {
+		things.del(ref)
+	}
+}
+
+// RefCount returns the number of strong references to the Thing.
+// It is zero for inactive Things.
+func (things *Things[Thing]) RefCount(ref ThingRef) uint32 {
+	if !things.IsNotNil(ref) || int(ref.idx) >= len(things.refCounts) {
+		return 0
+	}
+	return things.refCounts[ref.idx]
+}
+
+// resetRefCount makes the next Thing in the slot start at zero. Called when ref is deleted.
+func (things *Things[Thing]) resetRefCount(ref ThingRef) {
+	if int(ref.idx) < len(things.refCounts) {
+		things.refCounts[ref.idx] = 0
+	}
+}
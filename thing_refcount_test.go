@@ -0,0 +1,64 @@
+package ts_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+func TestRetainRelease(t *testing.T) {
+	things := ts.NewThings[Thing](4)
+	pattern := things.New(Thing{Kind: 7})
+	things.Retain(pattern)
+	things.Retain(pattern)
+	if got := things.RefCount(pattern); got != 2 {
+		t.Fatalf("expected RefCount 2, got %v", got)
+	}
+
+	things.Release(pattern)
+	if !things.IsNotNil(pattern) {
+		t.Fatal("expected Thing to stay alive while retained")
+	}
+	things.Release(pattern)
+	if things.IsNotNil(pattern) {
+		t.Fatal("expected Thing to be deleted by the last Release")
+	}
+
+	// the next Thing in the slot starts at zero.
+	reused := things.New(Thing{})
+	things.Retain(reused)
+	things.Delete(reused)
+	if got := things.RefCount(things.New(Thing{})); got != 0 {
+		t.Fatalf("expected new Thing to start at RefCount 0, got %v", got)
+	}
+}
+
+func TestReleaseStaleLogs(t *testing.T) {
+	if ts.BuildMode == "release" {
+		t.Skip("nothing is logged in release builds")
+	}
+	var buf bytes.Buffer
+	ts.SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer ts.SetLogger(nil)
+
+	things := ts.NewThings[Thing](4)
+	ref := things.New(Thing{})
+	things.Release(ref)
+	if !strings.Contains(buf.String(), "not retained") {
+		t.Fatalf("expected warning for Release of Thing that is not retained, got %q", buf.String())
+	}
+	if !things.IsNotNil(ref) {
+		t.Fatal("expected Thing that is not retained to stay alive")
+	}
+
+	buf.Reset()
+	things.Retain(ref)
+	things.Release(ref)
+	things.Release(ref)
+	if !strings.Contains(buf.String(), "already freed") {
+		t.Fatalf("expected warning for Release of freed Thing, got %q", buf.String())
+	}
+}
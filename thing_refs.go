@@ -0,0 +1,193 @@
+package ts
+
+import (
+	"reflect"
+	"strings"
+)
+
+// refIndex remembers which Things hold a ThingRef to which, so that refs to a deleted Thing
+// can be set to NilRef without scanning the whole pool.
+type refIndex struct {
+	// offsets of the ThingRef fields inside of Thing, found with reflection once.
+	offsets []uintptr
+	// target idx -> the fields that pointed to it when their Thing was last scanned.
+	// A field is numbered holder idx*len(offsets)+i, and is inside of the holders of one target at most.
+	holders [][]uint32
+	// field -> its value when its Thing was last scanned.
+	last []ThingRef
+	// field -> its position inside of the holders of the target in last, for removing it in O(1).
+	positions []uint32
+	// Things that were handed out by Get or Each this frame. Their pointers can still be written to,
+	// so they are scanned again on every Delete until EndFrame.
+	dirty     []bool
+	dirtyList []uint32
+}
+
+// EnableRefNulling makes the pool set every ThingRef field that points to a deleted Thing back to NilRef,
+// so Target, Owner, LastHitBy and similar fields never hold a stale ref.
+//
+// The ThingRef fields are found once with reflection, including inside nested structs and arrays.
+// Fields behind pointers, slices and maps are not scanned.
+//
+// Things keep a reverse index of who points at whom, so Delete does not scan the whole pool.
+// The index is updated on every Delete for the Things that were handed out by Get or Each this frame,
+// so call EndFrame once per frame to keep Delete fast.
+func (things *Things[Thing]) EnableRefNulling() {
+	if things.refs != nil {
+		return
+	}
+	offsets := refOffsets(reflect.TypeFor[Thing](), 0, nil)
+	if len(offsets) == 0 {
+		if !releaseMode {
+			logWarn(0, "EnableRefNulling: Thing does not have any ThingRef fields")
+		}
+		return
+	}
+	things.refs = &refIndex{offsets: offsets}
+	// Things created before this call have not been scanned yet.
+	for id := 1; id < len(things.used); id++ {
+		if things.used[id] {
+			things.touch(uint32(id))
+		}
+	}
+	things.deleteHooks = append(things.deleteHooks, things.nullRefsTo)
+}
+
+var thingRefType = reflect.TypeFor[ThingRef]()
+
+// refOffsets appends the offsets of all ThingRef fields inside of t, starting at base.
+func refOffsets(t reflect.Type, base uintptr, offsets []uintptr) []uintptr {
+	if t == thingRefType {
+		return append(offsets, base)
+	}
+	if t.PkgPath() == thingRefType.PkgPath() && (strings.HasPrefix(t.Name(), "List[") || strings.HasPrefix(t.Name(), "Tree[")) {
+		// List and Tree links are managed by the pool.
+		return offsets
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := range t.NumField() {
+			field := t.Field(i)
+			offsets = refOffsets(field.Type, base+field.Offset, offsets)
+		}
+	case reflect.Array:
+		elemOffsets := refOffsets(t.Elem(), 0, nil)
+		if len(elemOffsets) == 0 {
+			break
+		}
+		for i := range t.Len() {
+			for _, offset := range elemOffsets {
+				offsets = append(offsets, base+uintptr(i)*t.Elem().Size()+offset)
+			}
+		}
+	}
+	return offsets
+}
+
+// touch marks the Thing to be scanned again before the next Delete.
+func (things *Things[Thing]) touch(idx uint32) {
+	refs := things.refs
+	if int(idx) >= len(refs.dirty) {
+		refs.dirty = append(refs.dirty, make([]bool, int(idx)+1-len(refs.dirty))...)
+	}
+	if !refs.dirty[idx] {
+		refs.dirty[idx] = true
+		refs.dirtyList = append(refs.dirtyList, idx)
+	}
+}
+
+// scanDirty adds the refs held by the touched Things to the index.
+// The Things stay touched, because their pointers can still be written to until EndFrame.
+func (things *Things[Thing]) scanDirty() {
+	refs := things.refs
+	fields := len(refs.offsets)
+	for _, holder := range refs.dirtyList {
+		if !things.used[holder] {
+			continue
+		}
+		if need := (int(holder) + 1) * fields; need > len(refs.last) {
+			refs.last = append(refs.last, make([]ThingRef, need-len(refs.last))...)
+			refs.positions = append(refs.positions, make([]uint32, need-len(refs.positions))...)
+		}
+		thing := things.at(holder)
+		for i, offset := range refs.offsets {
+			field := holder*uint32(fields) + uint32(i)
+			target := *fieldAt[ThingRef](thing, offset)
+			if target == refs.last[field] {
+				continue
+			}
+			things.unindexField(field)
+			refs.last[field] = target
+			if !things.indexable(target) {
+				continue
+			}
+			if int(target.idx) >= len(refs.holders) {
+				refs.holders = append(refs.holders, make([][]uint32, int(target.idx)+1-len(refs.holders))...)
+			}
+			refs.positions[field] = uint32(len(refs.holders[target.idx]))
+			refs.holders[target.idx] = append(refs.holders[target.idx], field)
+		}
+	}
+}
+
+// endFrameRefs scans the touched Things one last time, and forgets them.
+// Pointers from Get and Each are not valid after the frame, so they cannot be written to anymore.
+func (things *Things[Thing]) endFrameRefs() {
+	things.scanDirty()
+	refs := things.refs
+	for _, holder := range refs.dirtyList {
+		refs.dirty[holder] = false
+	}
+	refs.dirtyList = refs.dirtyList[:0]
+}
+
+// indexable reports whether the index tracks fields that point to target.
+// It must not change when a growable pool grows, so it uses the limit.
+func (things *Things[Thing]) indexable(target ThingRef) bool {
+	return target.idx > 0 && target.idx < things.limit
+}
+
+// unindexField removes the field from the holders of the target it pointed to, and forgets its value.
+func (things *Things[Thing]) unindexField(field uint32) {
+	refs := things.refs
+	target := refs.last[field]
+	refs.last[field] = nilRef
+	if !things.indexable(target) {
+		return
+	}
+	holders := refs.holders[target.idx]
+	// swap the last field into its place.
+	moved := holders[len(holders)-1]
+	holders[refs.positions[field]] = moved
+	refs.positions[moved] = refs.positions[field]
+	refs.holders[target.idx] = holders[:len(holders)-1]
+}
+
+// nullRefsTo sets all the ThingRef fields that point to ref to NilRef, and removes the refs ref holds
+// from the index. Called when ref is deleted.
+func (things *Things[Thing]) nullRefsTo(ref ThingRef) {
+	things.scanDirty()
+	refs := things.refs
+	fields := uint32(len(refs.offsets))
+	if int(ref.idx) < len(refs.holders) {
+		// fields with a stale ref to an older Thing in this slot stay.
+		kept := refs.holders[ref.idx][:0]
+		for _, field := range refs.holders[ref.idx] {
+			if refs.last[field] != ref {
+				refs.positions[field] = uint32(len(kept))
+				kept = append(kept, field)
+				continue
+			}
+			*fieldAt[ThingRef](things.at(field/fields), refs.offsets[field%fields]) = nilRef
+			refs.last[field] = nilRef
+		}
+		refs.holders[ref.idx] = kept
+	}
+
+	// the refs held by the deleted Thing are gone too.
+	if end := (ref.idx + 1) * fields; int(end) <= len(refs.last) {
+		for field := ref.idx * fields; field < end; field++ {
+			things.unindexField(field)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package ts
+
+import "testing"
+
+type refThing struct {
+	Target    ThingRef
+	LastHitBy ThingRef
+}
+
+func TestRefIndexStaysBounded(t *testing.T) {
+	things := NewThings[refThing](64)
+	things.EnableRefNulling()
+	player := things.New(refThing{})
+
+	// short-lived bullets that hit the player.
+	for range 1000 {
+		bullet := things.New(refThing{Target: player})
+		things.Get(player).LastHitBy = bullet
+		things.Delete(bullet)
+	}
+	if n := len(things.refs.holders[player.idx]); n != 0 {
+		t.Fatalf("expected deleted bullets to be removed from the holders of the player, got %v", n)
+	}
+
+	// a turret that keeps retargeting.
+	turret := things.New(refThing{})
+	var targets []ThingRef
+	for range 32 {
+		targets = append(targets, things.New(refThing{}))
+	}
+	for i := range 1000 {
+		things.Get(turret).Target = targets[i%len(targets)]
+		things.Delete(things.New(refThing{})) // scans the turret.
+	}
+	total := 0
+	for _, holders := range things.refs.holders {
+		total += len(holders)
+	}
+	if total != 1 {
+		t.Fatalf("expected only the current target of the turret in the index, got %v fields", total)
+	}
+
+	current := things.Get(turret).Target
+	for _, target := range targets {
+		if target != current {
+			things.Delete(target)
+		}
+	}
+	if things.Get(turret).Target != current {
+		t.Fatal("expected deleting old targets to leave the current one alone")
+	}
+	things.Delete(current)
+	if things.Get(turret).Target != nilRef {
+		t.Fatal("expected Target to be nulled when the current target is deleted")
+	}
+}
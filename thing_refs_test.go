@@ -0,0 +1,113 @@
+package ts_test
+
+import (
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+// Unit is a Thing used for testing ref nulling.
+type Unit struct {
+	Target ts.ThingRef
+	Combat struct {
+		LastHitBy ts.ThingRef
+		Hp        int
+	}
+	Squad [3]ts.ThingRef
+	Group ts.List[Unit]
+}
+
+func TestRefNulling(t *testing.T) {
+	things := ts.NewThings[Unit](8)
+	things.EnableRefNulling()
+
+	player := things.New(Unit{})
+	enemy := things.New(Unit{Target: player})
+	ally := things.New(Unit{})
+	things.Get(ally).Combat.LastHitBy = enemy
+	things.Get(ally).Squad = [3]ts.ThingRef{player, enemy, player}
+	things.Get(player).Group.Init(player, things)
+	things.Get(player).Group.Append(enemy)
+
+	things.Delete(player)
+	if got := things.Get(enemy).Target; got != (ts.ThingRef{}) {
+		t.Fatalf("expected Target to be nulled, got %v", got)
+	}
+	if got := things.Get(ally).Squad; got != [3]ts.ThingRef{{}, enemy, {}} {
+		t.Fatalf("expected refs to player in array to be nulled, got %v", got)
+	}
+	if got := things.Get(ally).Combat.LastHitBy; got != enemy {
+		t.Fatalf("expected ref to alive Thing to stay, got %v", got)
+	}
+
+	// the ref was changed after it was indexed.
+	things.Get(ally).Combat.LastHitBy = ally
+	things.Delete(enemy)
+	if got := things.Get(ally).Combat.LastHitBy; got != ally {
+		t.Fatalf("expected ref that was changed to stay, got %v", got)
+	}
+	if got := things.Get(ally).Squad; got != [3]ts.ThingRef{} {
+		t.Fatalf("expected refs to enemy in nested array to be nulled, got %v", got)
+	}
+}
+
+func TestRefNullingEnabledLater(t *testing.T) {
+	things := ts.NewThings[Unit](8)
+	player := things.New(Unit{})
+	var enemies []ts.ThingRef
+	for range 3 {
+		enemies = append(enemies, things.New(Unit{Target: player}))
+	}
+	things.EnableRefNulling()
+
+	things.Delete(player)
+	for _, enemy := range enemies {
+		if got := things.Get(enemy).Target; got != (ts.ThingRef{}) {
+			t.Fatalf("expected Things created before EnableRefNulling to be nulled, got %v", got)
+		}
+	}
+	// reused slot, the new Thing was not targeted.
+	reused := things.New(Unit{})
+	things.Get(enemies[0]).Target = reused
+	things.Delete(enemies[1])
+	if got := things.Get(enemies[0]).Target; got != reused {
+		t.Fatalf("expected Target to stay, got %v", got)
+	}
+}
+
+func TestRefNullingWriteAfterDelete(t *testing.T) {
+	things := ts.NewThings[Unit](8)
+	things.EnableRefNulling()
+	player := things.New(Unit{})
+	bullet := things.New(Unit{})
+	turret := things.New(Unit{})
+	tower := things.New(Unit{})
+
+	// deleting inside of Each, and writing through the pointer after the Delete.
+	for ref, unit := range things.Each() {
+		if ref == turret {
+			things.Delete(bullet)
+			unit.Target = player
+		}
+	}
+	// the same with a pointer from Get.
+	p := things.Get(tower)
+	things.Delete(things.New(Unit{}))
+	p.Target = player
+
+	things.Delete(player)
+	for _, ref := range []ts.ThingRef{turret, tower} {
+		if got := things.Get(ref).Target; got != (ts.ThingRef{}) {
+			t.Fatalf("expected Target written after a Delete to be nulled, got %v", got)
+		}
+	}
+
+	// writes after the last Delete of the frame are scanned by EndFrame.
+	enemy := things.New(Unit{})
+	things.Get(turret).Target = enemy
+	things.EndFrame()
+	things.Delete(enemy)
+	if got := things.Get(turret).Target; got != (ts.ThingRef{}) {
+		t.Fatalf("expected Target written before EndFrame to be nulled, got %v", got)
+	}
+}
@@ -0,0 +1,107 @@
+package ts
+
+// ReusePolicy decides which free slot New uses.
+//
+// Reusing a slot bumps its generation, so stale refs to the deleted Thing are still detected,
+// but a stale *Thing pointer silently points at the new Thing.
+// The longer a freed slot stays unused, the more likely such bugs get noticed.
+type ReusePolicy uint8
+
+const (
+	// ReuseLowest uses the free slot with the lowest index. This is the default.
+	// It keeps the active Things close together, which is fastest to loop over.
+	ReuseLowest ReusePolicy = iota
+	// ReuseLIFO uses the slot that was freed last.
+	ReuseLIFO
+	// ReuseFIFO uses the slot that was freed first, so freed slots stay unused for as long as possible.
+	ReuseFIFO
+	// ReuseQuarantine works like ReuseFIFO, but freed slots can't be used
+	// until the Quarantine has passed. New acts like the pool is full until then.
+	ReuseQuarantine
+)
+
+// Quarantine is how long freed slots stay unusable with ReuseQuarantine.
+// Both have to pass. Zero means no waiting.
+type Quarantine struct {
+	// Frames is the number of EndFrame calls.
+	Frames uint64
+	// Deletions is the number of Things deleted after the slot was freed.
+	Deletions uint64
+}
+
+// freedSlot is a slot waiting to be reused.
+type freedSlot struct {
+	idx uint32
+	// the slot can be used once frame and deletions reach these.
+	readyFrame, readyDeletion uint64
+}
+
+// SetReusePolicy sets which free slot New uses.
+// The optional Quarantine is used with ReuseQuarantine.
+//
+//	// debug builds keep freed slots unused for 2 seconds
+//	things.SetReusePolicy(ts.ReuseQuarantine, ts.Quarantine{Frames: 120})
+func (things *Things[Thing]) SetReusePolicy(policy ReusePolicy, quarantine_OPTIONAL ...Quarantine) {
+	things.reusePolicy = policy
+	things.quarantine = Quarantine{}
+	if len(quarantine_OPTIONAL) > 0 {
+		things.quarantine = quarantine_OPTIONAL[0]
+	}
+	// the slots that are free right now can be used right away.
+	things.freed = things.freed[:0]
+	things.freedStart = 0
+	if policy != ReuseLowest {
+		things.addFreeSlots(1, uint32(len(things.used)))
+	}
+}
+
+// addFreeSlots makes the unused slots from start up to end ready to be used, before the freed ones.
+func (things *Things[Thing]) addFreeSlots(start, end uint32) {
+	var free []freedSlot
+	for idx := start; idx < end; idx++ {
+		if !things.used[idx] {
+			free = append(free, freedSlot{idx, things.frame, things.deletions})
+		}
+	}
+	things.freed = append(free, things.freed[things.freedStart:]...)
+	things.freedStart = 0
+}
+
+// release adds a slot freed by del.
+func (things *Things[Thing]) release(idx uint32) {
+	things.freed = append(things.freed, freedSlot{
+		idx:           idx,
+		readyFrame:    things.frame + things.quarantine.Frames,
+		readyDeletion: things.deletions + things.quarantine.Deletions,
+	})
+}
+
+// takeFree returns a free slot by the reuse policy, or NilRef if there is none.
+func (things *Things[Thing]) takeFree() ThingRef {
+	if things.freedStart == len(things.freed) {
+		return nilRef
+	}
+	var slot freedSlot
+	switch things.reusePolicy {
+	case ReuseLIFO:
+		slot = things.freed[len(things.freed)-1]
+		things.freed = things.freed[:len(things.freed)-1]
+	case ReuseQuarantine:
+		// slots are freed in order, so if the first one is not ready none are.
+		slot = things.freed[things.freedStart]
+		if things.frame < slot.readyFrame || things.deletions < slot.readyDeletion {
+			return nilRef
+		}
+		fallthrough
+	default: // FIFO
+		slot = things.freed[things.freedStart]
+		things.freedStart++
+		// drop the used up slots, so the queue does not grow forever.
+		if things.freedStart > len(things.freed)/2 {
+			n := copy(things.freed, things.freed[things.freedStart:])
+			things.freed = things.freed[:n]
+			things.freedStart = 0
+		}
+	}
+	return ThingRef{slot.idx, things.generations[slot.idx]}
+}
@@ -0,0 +1,125 @@
+package ts_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+// slotsReused deletes b, a, c in order and returns which of them got their slot reused by the next New.
+func slotsReused(t *testing.T, policy ts.ReusePolicy) string {
+	t.Helper()
+	things := ts.NewThings[Thing](3)
+	things.SetReusePolicy(policy)
+	slots := make(map[*Thing]string)
+	var refs []ts.ThingRef
+	for _, name := range []string{"a", "b", "c"} {
+		ref := things.New(Thing{})
+		slots[things.Get(ref)] = name
+		refs = append(refs, ref)
+	}
+	things.Delete(refs[1], refs[0], refs[2])
+	return slots[things.Get(things.New(Thing{}))]
+}
+
+func TestReusePolicies(t *testing.T) {
+	for policy, want := range map[ts.ReusePolicy]string{
+		ts.ReuseLowest: "a",
+		ts.ReuseLIFO:   "c",
+		ts.ReuseFIFO:   "b",
+	} {
+		if got := slotsReused(t, policy); got != want {
+			t.Fatalf("expected policy %v to reuse the slot of %v, got %v", policy, want, got)
+		}
+	}
+}
+
+func TestReuseQuarantine(t *testing.T) {
+	things := ts.NewThings[Thing](2)
+	things.SetReusePolicy(ts.ReuseQuarantine, ts.Quarantine{Frames: 2})
+	a := things.New(Thing{})
+	things.New(Thing{})
+	things.Delete(a)
+
+	if ref := things.New(Thing{}); ref != (ts.ThingRef{}) {
+		t.Fatalf("expected freed slot to be quarantined, got %v", ref)
+	}
+	things.EndFrame()
+	if ref := things.New(Thing{}); ref != (ts.ThingRef{}) {
+		t.Fatalf("expected freed slot to be quarantined for 2 frames, got %v", ref)
+	}
+	things.EndFrame()
+	if ref := things.New(Thing{}); ref == (ts.ThingRef{}) {
+		t.Fatal("expected freed slot to be reused after the quarantine")
+	}
+}
+
+func TestReuseQuarantineFullLogs(t *testing.T) {
+	if ts.BuildMode == "release" {
+		t.Skip("nothing is logged in release builds")
+	}
+	var buf bytes.Buffer
+	ts.SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer ts.SetLogger(nil)
+
+	things := ts.NewThings[Thing](2)
+	things.SetReusePolicy(ts.ReuseQuarantine, ts.Quarantine{Frames: 1})
+	a, b := things.New(Thing{}), things.New(Thing{})
+	things.Delete(a, b)
+	things.New(Thing{})
+	if got := buf.String(); !strings.Contains(got, "in quarantine") || !strings.Contains(got, "quarantined=2") {
+		t.Fatalf("expected error about quarantined slots, got %q", got)
+	}
+
+	buf.Reset()
+	things.EndFrame()
+	things.New(Thing{})
+	things.New(Thing{})
+	things.New(Thing{})
+	if got := buf.String(); !strings.Contains(got, "Ran out of memory") || strings.Contains(got, "quarantine") {
+		t.Fatalf("expected out of memory error without quarantined slots, got %q", got)
+	}
+}
+
+func TestReuseQuarantineDeletions(t *testing.T) {
+	things := ts.NewThings[Thing](4)
+	things.SetReusePolicy(ts.ReuseQuarantine, ts.Quarantine{Deletions: 2})
+	refs := []ts.ThingRef{things.New(Thing{}), things.New(Thing{}), things.New(Thing{}), things.New(Thing{})}
+
+	things.Delete(refs[0])
+	things.Delete(refs[1])
+	if ref := things.New(Thing{}); ref != (ts.ThingRef{}) {
+		t.Fatalf("expected freed slot to wait for 2 more deletions, got %v", ref)
+	}
+	things.Delete(refs[2])
+	if ref := things.New(Thing{}); ref == (ts.ThingRef{}) {
+		t.Fatal("expected first freed slot to be reused after 2 more deletions")
+	}
+	if ref := things.New(Thing{}); ref != (ts.ThingRef{}) {
+		t.Fatalf("expected later freed slots to still be quarantined, got %v", ref)
+	}
+}
+
+func TestReusePolicyGrowable(t *testing.T) {
+	things := ts.NewGrowableThings[Thing](4, 16)
+	things.SetReusePolicy(ts.ReuseQuarantine, ts.Quarantine{Frames: 100})
+	var refs []ts.ThingRef
+	for range 10 {
+		refs = append(refs, things.New(Thing{}))
+	}
+	things.Delete(refs...)
+	// quarantined slots are skipped, the pool grows instead.
+	for range 6 {
+		if ref := things.New(Thing{}); ref == (ts.ThingRef{}) {
+			t.Fatal("expected pool to grow while freed slots are quarantined")
+		}
+	}
+	for _, ref := range refs {
+		if things.IsNotNil(ref) {
+			t.Fatal("expected quarantined slots to stay unused")
+		}
+	}
+}
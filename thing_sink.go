@@ -0,0 +1,79 @@
+package ts
+
+import (
+	"bytes"
+	"cmp"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"unsafe"
+)
+
+// maxSinkSites is how many different call sites of Get on NilRef are remembered per frame.
+const maxSinkSites = 8
+
+// EnableNilSink catches writes to the Nil Thing, like things.Get(deadRef).Health -= 1.
+//
+// Get on a NilRef normally returns a pointer to a copy of the Nil Thing, so writes are silently lost.
+// With the sink, it returns a pointer to one shared Thing instead, which is compared to the Nil Thing at EndFrame.
+// Fields that were changed are logged as an error, with the call sites of Get, and the sink is reset.
+func (things *Things[Thing]) EnableNilSink() {
+	if things.sink != nil {
+		return
+	}
+	things.sink = new(Thing)
+	*things.sink = *things.at(0)
+}
+
+// recordNilGet remembers the caller of Get on NilRef.
+func (things *Things[Thing]) recordNilGet() {
+	var pc [1]uintptr
+	// skip runtime.Callers, recordNilGet and Get.
+	runtime.Callers(3, pc[:])
+	if len(things.sinkSites) < maxSinkSites && !slices.Contains(things.sinkSites, pc[0]) {
+		things.sinkSites = append(things.sinkSites, pc[0])
+	}
+}
+
+// checkSink reports and resets the fields of the sink that are not the same as the Nil Thing.
+func (things *Things[Thing]) checkSink() {
+	fields := changedFields(reflect.ValueOf(things.sink).Elem(), reflect.ValueOf(things.at(0)).Elem(), "", nil)
+	if len(fields) > 0 && !releaseMode {
+		sites := make([]string, len(things.sinkSites))
+		for i, pc := range things.sinkSites {
+			sites[i] = callSite(pc)
+		}
+		logError(1, "Writes to the Nil Thing were discarded, a NilRef was used to modify a Thing",
+			"fields", strings.Join(fields, ", "), "get", strings.Join(sites, ", "))
+	}
+	*things.sink = *things.at(0)
+	things.sinkSites = things.sinkSites[:0]
+}
+
+// changedFields appends the names of the fields of got that are not equal to want.
+// Nested structs are compared field by field. got and want must be addressable.
+//
+// Fields are compared by their bytes, because the sink starts as a copy of the Nil Thing:
+// any difference is a write. Comparing values would report NaN floats and funcs, which never equal themselves.
+func changedFields(got, want reflect.Value, prefix string, fields []string) []string {
+	if got.Kind() != reflect.Struct {
+		if !bytes.Equal(valueBytes(got), valueBytes(want)) {
+			fields = append(fields, cmp.Or(prefix, got.Type().String()))
+		}
+		return fields
+	}
+	for i := range got.NumField() {
+		name := got.Type().Field(i).Name
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		fields = changedFields(got.Field(i), want.Field(i), name, fields)
+	}
+	return fields
+}
+
+// valueBytes returns the memory of an addressable value.
+func valueBytes(v reflect.Value) []byte {
+	return unsafe.Slice((*byte)(unsafe.Pointer(v.UnsafeAddr())), v.Type().Size())
+}
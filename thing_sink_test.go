@@ -0,0 +1,93 @@
+package ts_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+func TestNilSink(t *testing.T) {
+	if ts.BuildMode == "release" {
+		t.Skip("nothing is logged in release builds")
+	}
+	var buf bytes.Buffer
+	ts.SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelError})))
+	defer ts.SetLogger(nil)
+
+	things := ts.NewThings(4, Thing{Health: 100})
+	things.EnableNilSink()
+	dead := things.New(Thing{})
+	things.Delete(dead)
+
+	things.Get(dead).Health -= 1
+	_, file, line, _ := runtime.Caller(0)
+	getSite := fmt.Sprintf("%s:%d", filepath.Base(file), line-1)
+	things.Get(dead).Position.X = 5
+	if got := things.Get(dead).Health; got != 99 {
+		t.Fatalf("expected writes to the sink to be kept until EndFrame, got %v", got)
+	}
+	things.EndFrame()
+	log := buf.String()
+	for _, want := range []string{"Health", "Position.X", getSite} {
+		if !strings.Contains(log, want) {
+			t.Fatalf("expected report to contain %q, got %q", want, log)
+		}
+	}
+	if strings.Contains(log, "Kind") {
+		t.Fatalf("expected only changed fields to be reported, got %q", log)
+	}
+	if got := things.Get(dead).Health; got != 100 {
+		t.Fatalf("expected sink to be reset to the Nil Thing, got %v", got)
+	}
+
+	buf.Reset()
+	_ = things.Get(dead).Health // reading is fine
+	things.EndFrame()
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing to be reported without writes, got %q", buf.String())
+	}
+}
+
+// Spell has fields that are not equal to themselves when compared as values.
+type Spell struct {
+	Power  float64
+	OnCast func()
+	Name   string
+}
+
+func TestNilSinkNaNAndFunc(t *testing.T) {
+	if ts.BuildMode == "release" {
+		t.Skip("nothing is logged in release builds")
+	}
+	var buf bytes.Buffer
+	ts.SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelError})))
+	defer ts.SetLogger(nil)
+
+	things := ts.NewThings(4, Spell{Power: math.NaN(), OnCast: func() {}})
+	things.EnableNilSink()
+	things.Get(ts.ThingRef{}).OnCast()
+	things.EndFrame()
+	if buf.Len() != 0 {
+		t.Fatalf("expected NaN and func fields of the Nil Thing to not be reported, got %q", buf.String())
+	}
+
+	things.Get(ts.ThingRef{}).Name = "fireball"
+	things.EndFrame()
+	if log := buf.String(); !strings.Contains(log, "fields=Name") {
+		t.Fatalf("expected only Name to be reported, got %q", log)
+	}
+}
+
+func TestNilThingState(t *testing.T) {
+	things := ts.NewThings(4, Thing{Health: 42})
+	if got := things.Get(ts.ThingRef{}).Health; got != 42 {
+		t.Fatalf("expected Nil Thing to have the state passed to NewThings, got %v", got)
+	}
+}
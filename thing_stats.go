@@ -0,0 +1,193 @@
+package ts
+
+import (
+	"expvar"
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// Stats describes how a pool is used, to help choose its size.
+type Stats struct {
+	// Capacity is the number of Things that fit without growing.
+	Capacity uint
+	// MaxCapacity is the number of Things a growable pool can grow to. Same as Capacity otherwise.
+	MaxCapacity uint
+	Live        uint
+	PeakLive    uint
+	// News and Deletes are the number of Things created and deleted since the pool was created.
+	News, Deletes uint64
+	FreeSlots     uint
+	// Fragmentation is the share of slots below the highest live Thing that are free, from 0 to 1.
+	// High fragmentation makes looping over the Things slower.
+	Fragmentation float64
+	// MaxGeneration is the highest generation of any slot, the number of times it was reused.
+	MaxGeneration uint32
+	// Lists has the stats of every List field.
+	Lists []ListStats
+	// MemoryBytes is an estimate of the memory used by the Things and the tables next to them.
+	MemoryBytes uintptr
+}
+
+// ListStats describes how one List field of Thing is used.
+type ListStats struct {
+	Field string
+	// Lists is the number of Things that own an initialized List in this field.
+	Lists int
+	// Members is the number of Things that are inside one of those Lists.
+	Members int
+}
+
+// Stats walks the pool and returns its Stats.
+func (things *Things[Thing]) Stats() Stats {
+	stats := Stats{
+		Capacity:    things.Capacity(),
+		MaxCapacity: uint(things.limit) - 1,
+		Live:        things.activeThings,
+		PeakLive:    things.peakActive,
+		News:        things.news,
+		Deletes:     things.deletions,
+	}
+
+	highest := 0
+	for idx := 1; idx < len(things.used); idx++ {
+		stats.MaxGeneration = max(stats.MaxGeneration, things.generations[idx])
+		if things.used[idx] {
+			highest = idx
+		}
+	}
+	stats.FreeSlots = stats.Capacity - stats.Live
+	if highest > 0 {
+		stats.Fragmentation = float64(uint(highest)-stats.Live) / float64(highest)
+	}
+
+	for _, offset := range things.listOffsets {
+		list := ListStats{Field: fieldName(reflect.TypeFor[Thing](), offset, reflect.TypeFor[List[Thing]]())}
+		for idx := 1; idx < len(things.used); idx++ {
+			if !things.used[idx] {
+				continue
+			}
+			node := fieldAt[List[Thing]](things.at(uint32(idx)), offset)
+			if node.isInitialized && node.owner().idx == uint32(idx) {
+				list.Lists++
+			}
+			if node.inList() {
+				list.Members++
+			}
+		}
+		stats.Lists = append(stats.Lists, list)
+	}
+
+	stats.MemoryBytes = things.memoryBytes()
+	return stats
+}
+
+// memoryBytes estimates the memory used by the pool.
+func (things *Things[Thing]) memoryBytes() uintptr {
+	var thing Thing
+	size := uintptr(things.maxThings) * unsafe.Sizeof(thing)
+	size += sliceBytes(things.used) + sliceBytes(things.generations)
+	size += sliceBytes(things.listGroups) + sliceBytes(things.freeGroups)
+	size += sliceBytes(things.refCounts) + sliceBytes(things.ages) + sliceBytes(things.freed)
+	size += sliceBytes(things.leakSites) + sliceBytes(things.leakTicks) + sliceBytes(things.deleting)
+	if refs := things.refs; refs != nil {
+		size += sliceBytes(refs.last) + sliceBytes(refs.positions) + sliceBytes(refs.dirty) + sliceBytes(refs.dirtyList)
+		for _, holders := range refs.holders {
+			size += sliceBytes(holders)
+		}
+	}
+	if poison := things.poison; poison != nil {
+		size += sliceBytes(poison.poisoned) + sliceBytes(poison.getSites) + sliceBytes(poison.found)
+	}
+	return size
+}
+
+func sliceBytes[T any](s []T) uintptr {
+	var v T
+	return uintptr(cap(s)) * unsafe.Sizeof(v)
+}
+
+// fieldName returns the dotted name of the field of type want at offset inside of t.
+func fieldName(t reflect.Type, offset uintptr, want reflect.Type) string {
+	if t.Kind() == reflect.Struct {
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if offset < field.Offset || offset >= field.Offset+field.Type.Size() {
+				continue
+			}
+			if field.Type == want && offset == field.Offset {
+				return field.Name
+			}
+			if name := fieldName(field.Type, offset-field.Offset, want); name != "" {
+				return field.Name + "." + name
+			}
+		}
+	}
+	if t.Kind() == reflect.Array && t.Elem().Size() > 0 {
+		i := offset / t.Elem().Size()
+		if name := fieldName(t.Elem(), offset-i*t.Elem().Size(), want); name != "" {
+			return fmt.Sprintf("%d.%s", i, name)
+		}
+		if t.Elem() == want && offset%t.Elem().Size() == 0 {
+			return fmt.Sprint(i)
+		}
+	}
+	return ""
+}
+
+// publishedStats are the Stats of the last EndFrame.
+// They can be read from other goroutines, like the one serving expvar.
+type publishedStats struct {
+	mu    sync.Mutex
+	stats Stats
+}
+
+func (published *publishedStats) get() Stats {
+	published.mu.Lock()
+	defer published.mu.Unlock()
+	return published.stats
+}
+
+// PublishExpvar publishes the Stats with expvar under the name, like "things" or "particles".
+// They are updated at every EndFrame, so reading them from the expvar handler is safe.
+// The name must not be used by another expvar.
+//
+//	import _ "net/http/pprof" // serves /debug/vars too
+//	things.PublishExpvar("things")
+func (things *Things[Thing]) PublishExpvar(name string) {
+	if expvar.Get(name) != nil {
+		if !releaseMode {
+			logError(0, "Tried to PublishExpvar with a name that is already used", "name", name)
+		}
+		return
+	}
+	things.PublishStats()
+	expvar.Publish(name, expvar.Func(func() any { return things.FrameStats() }))
+}
+
+// PublishStats makes every EndFrame save the Stats, so FrameStats can be read from other goroutines.
+// Call it from the goroutine that uses the pool, before the others read them.
+func (things *Things[Thing]) PublishStats() {
+	if things.published == nil {
+		things.published = &publishedStats{stats: things.Stats()}
+	}
+}
+
+// FrameStats returns the Stats saved at the last EndFrame.
+// Unlike Stats, it is safe to call from other goroutines, like an HTTP handler.
+// It returns zero Stats if PublishStats was not called.
+func (things *Things[Thing]) FrameStats() Stats {
+	if things.published == nil {
+		return Stats{}
+	}
+	return things.published.get()
+}
+
+// updatePublishedStats is called by EndFrame.
+func (things *Things[Thing]) updatePublishedStats() {
+	stats := things.Stats()
+	things.published.mu.Lock()
+	things.published.stats = stats
+	things.published.mu.Unlock()
+}
@@ -0,0 +1,70 @@
+package ts_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+func TestStats(t *testing.T) {
+	things := ts.NewThings[Thing](8)
+	plr := things.New(Thing{Kind: KindPlayer})
+	things.Get(plr).Inventory.Init(plr, things)
+	var items []ts.ThingRef
+	for range 4 {
+		item := things.New(Thing{Kind: KindItem})
+		things.Get(plr).Inventory.Append(item)
+		items = append(items, item)
+	}
+	things.Delete(items[0], items[1])
+	things.New(Thing{}) // reuses the slot of items[0]
+
+	stats := things.Stats()
+	if stats.Capacity != 8 || stats.MaxCapacity != 8 || stats.Live != 4 || stats.PeakLive != 5 {
+		t.Fatalf("expected capacity 8, 4 live and a peak of 5, got %+v", stats)
+	}
+	if stats.News != 6 || stats.Deletes != 2 || stats.FreeSlots != 4 || stats.MaxGeneration != 1 {
+		t.Fatalf("expected 6 news, 2 deletes, 4 free slots and generation 1, got %+v", stats)
+	}
+	// slots 1..5 are used, except for the slot of items[1].
+	if stats.Fragmentation != 0.2 {
+		t.Fatalf("expected fragmentation 0.2, got %v", stats.Fragmentation)
+	}
+	if len(stats.Lists) != 1 || stats.Lists[0] != (ts.ListStats{Field: "Inventory", Lists: 1, Members: 2}) {
+		t.Fatalf("expected Inventory with 2 members, got %+v", stats.Lists)
+	}
+	if stats.MemoryBytes < 9*uintptr(256) {
+		t.Fatalf("expected memory of at least the Things, got %v", stats.MemoryBytes)
+	}
+}
+
+var expvarTests int
+
+func TestPublishExpvar(t *testing.T) {
+	// a new name every run, expvars can't be removed.
+	expvarTests++
+	name := fmt.Sprintf("test_things_%d", expvarTests)
+	things := ts.NewThings[Thing](8)
+	things.PublishExpvar(name)
+	things.New(Thing{})
+	var stats ts.Stats
+	read := func() {
+		if err := json.Unmarshal([]byte(expvar.Get(name).String()), &stats); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read()
+	if stats.Live != 0 {
+		t.Fatalf("expected published stats to wait for EndFrame, got %v live", stats.Live)
+	}
+	things.EndFrame()
+	read()
+	if stats.Live != 1 {
+		t.Fatalf("expected published stats to be updated at EndFrame, got %v live", stats.Live)
+	}
+	// the name is taken, this only logs.
+	ts.NewThings[Thing](1).PublishExpvar(name)
+}
@@ -18,8 +18,8 @@ func TestNewThingsBasicLayout(t *testing.T) {
 	if int(th.maxThings) != 4 {
 		t.Fatalf("expected maxThings 4, got %v", th.maxThings)
 	}
-	if len(th.things) != 4 {
-		t.Fatalf("expected things length 4, got %v", len(th.things))
+	if len(th.chunks) != 1 || len(th.chunks[0]) != 4 {
+		t.Fatalf("expected one chunk of length 4, got %v chunks", len(th.chunks))
 	}
 	if len(th.used) != 4 {
 		t.Fatalf("expected used length 4, got %v", len(th.used))
@@ -0,0 +1,64 @@
+package ts
+
+// validate checks that every List in the pool is linked correctly, and logs an error for each broken one.
+// EndFrame calls it in debug builds, it returns the number of broken Lists.
+func (things *Things[Thing]) validate() (broken int) {
+	for idx := uint32(1); idx < uint32(len(things.used)); idx++ {
+		if !things.used[idx] {
+			continue
+		}
+		owner := ThingRef{idx, things.generations[idx]}
+		for _, offset := range things.listOffsets {
+			head := fieldAt[List[Thing]](things.at(idx), offset)
+			if !head.isInitialized || head.owner() != owner {
+				continue
+			}
+			if problem := things.validateList(head); problem != "" {
+				broken++
+				if !releaseMode {
+					logError(1, "List is broken: "+problem, "owner", owner, "offset", offset)
+				}
+			}
+		}
+	}
+	return broken
+}
+
+// validateList walks the List of head, and describes the first problem it finds.
+func (things *Things[Thing]) validateList(head *List[Thing]) string {
+	if head.first == nilRef {
+		if head.length != 0 {
+			return "empty List has a length"
+		}
+		return ""
+	}
+	count := 0
+	prev := fieldAt[List[Thing]](things.at(head.first.idx), head.offset).prev
+	for ref := head.first; ; {
+		if !things.IsNotNil(ref) {
+			return "links to a deleted Thing"
+		}
+		node := fieldAt[List[Thing]](things.at(ref.idx), head.offset)
+		if node.owner() != head.owner() {
+			return "member has a different owner"
+		}
+		if node.prev != prev {
+			return "next and prev links do not match"
+		}
+		count++
+		if count > head.length {
+			return "has more members than its length"
+		}
+		prev, ref = ref, node.next
+		if ref == head.first {
+			break
+		}
+	}
+	if fieldAt[List[Thing]](things.at(head.first.idx), head.offset).prev != prev {
+		return "first does not link back to the last member"
+	}
+	if count != head.length {
+		return "has less members than its length"
+	}
+	return ""
+}
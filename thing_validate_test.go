@@ -0,0 +1,28 @@
+package ts
+
+import "testing"
+
+type validateThing struct {
+	Items List[validateThing]
+}
+
+func TestValidateFindsBrokenLists(t *testing.T) {
+	things := NewThings[validateThing](8)
+	owner := things.New(validateThing{})
+	things.Get(owner).Items.Init(owner, things)
+	a, b := things.New(validateThing{}), things.New(validateThing{})
+	things.Get(owner).Items.Append(a, b)
+	if broken := things.validate(); broken != 0 {
+		t.Fatalf("expected correct List to be valid, got %v broken", broken)
+	}
+
+	things.Get(owner).Items.length = 3
+	if broken := things.validate(); broken != 1 {
+		t.Fatalf("expected wrong length to be found, got %v broken", broken)
+	}
+	things.Get(owner).Items.length = 2
+	things.Get(b).Items.prev = b
+	if broken := things.validate(); broken != 1 {
+		t.Fatalf("expected wrong prev link to be found, got %v broken", broken)
+	}
+}
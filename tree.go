@@ -0,0 +1,237 @@
+package ts
+
+import (
+	"iter"
+	"unsafe"
+)
+
+// Tree is supposed to be embedded inside of your Thing type.
+// Tree links Things into a hierarchy of parents and children of any depth.
+// (player -> weapon -> attachments)
+//
+// Deleting a Thing detaches it from its parent, and its children become roots.
+// Use Things.DeleteRecursive to delete the whole subtree instead.
+type Tree[Thing any] struct {
+	things *Things[Thing]
+	self   ThingRef
+	offset uintptr // offset of this Tree within the Thing struct.
+
+	parent ThingRef
+	// the first child. Children are a circular list linked through their siblings.
+	firstChild ThingRef
+	// the next sibling. If we are the last child then next==firstChild of our parent.
+	next ThingRef
+	// the previous sibling. If we are the first child then prev is the last child.
+	prev ThingRef
+}
+
+// Init initializes the Tree. It must be called before SetParent.
+// Parents get initialized automatically.
+func (curr *Tree[Thing]) Init(selfRef ThingRef, things *Things[Thing]) (self *Thing) {
+	if !things.IsNotNil(selfRef) {
+		return things.get(nilRef)
+	}
+	if curr.self != nilRef {
+		if !releaseMode {
+			logError(0, "Tree is already initialized")
+		}
+		return things.get(nilRef)
+	}
+	self = things.get(selfRef)
+
+	// compute and validate the offset of this Tree field inside the owner struct
+	offset := uintptr(unsafe.Pointer(curr)) - uintptr(unsafe.Pointer(self))
+	if offset+unsafe.Sizeof(*curr) > unsafe.Sizeof(*self) {
+		if !releaseMode {
+			logError(0, "Incorrect owner ThingRef passed")
+		}
+		return things.get(nilRef)
+	}
+	curr.init(selfRef, things, offset)
+	return self
+}
+
+// SetParent moves this Thing under parent, as its last child.
+// Passing a NilRef detaches this Thing from its parent.
+// Setting a Thing as a child of itself or of one of its descendants is not allowed.
+func (curr *Tree[Thing]) SetParent(parent ThingRef) {
+	if curr.self == nilRef {
+		if !releaseMode {
+			logWarn(0, "Tried to SetParent on uninitialized tree")
+		}
+		return
+	}
+	if parent == nilRef {
+		curr.detach()
+		return
+	}
+	if !curr.things.IsNotNil(parent) {
+		if !releaseMode {
+			logWarn(0, "Tried to SetParent to inactive Thing")
+		}
+		return
+	}
+	for ancestor := parent; ancestor != nilRef; ancestor = curr.getTreeDataFromThing(ancestor).parent {
+		if ancestor == curr.self {
+			if !releaseMode {
+				logWarn(0, "Tried to SetParent to a descendant, this would create a cycle")
+			}
+			return
+		}
+	}
+	curr.detach()
+
+	parentNode := curr.getTreeDataFromThing(parent)
+	if parentNode.self == nilRef {
+		parentNode.init(parent, curr.things, curr.offset)
+	}
+	curr.parent = parent
+	if parentNode.firstChild == nilRef {
+		// the only child. Links to itself.
+		curr.next, curr.prev = curr.self, curr.self
+		parentNode.firstChild = curr.self
+		return
+	}
+	// First->Prev is the last child.
+	first := curr.getTreeDataFromThing(parentNode.firstChild)
+	last := curr.getTreeDataFromThing(first.prev)
+	// Last <-> Self <-> First
+	curr.prev = first.prev
+	curr.next = parentNode.firstChild
+	last.next = curr.self
+	first.prev = curr.self
+}
+
+// Parent returns the parent of this Thing. It is NilRef for roots.
+func (curr *Tree[Thing]) Parent() ThingRef {
+	return curr.parent
+}
+
+// Children iterates over the direct children of this Thing.
+//
+// Changing the parent of the current child while looping is unsafe.
+func (curr *Tree[Thing]) Children() iter.Seq2[ThingRef, *Thing] {
+	return func(yield func(ThingRef, *Thing) bool) {
+		if curr.firstChild == nilRef {
+			return
+		}
+		for child := curr.firstChild; ; {
+			if !yield(child, curr.things.get(child)) {
+				return
+			}
+			child = curr.getTreeDataFromThing(child).next
+			if child == curr.firstChild || child == nilRef {
+				return
+			}
+		}
+	}
+}
+
+// Ancestors iterates from the parent of this Thing up to the root.
+func (curr *Tree[Thing]) Ancestors() iter.Seq2[ThingRef, *Thing] {
+	return func(yield func(ThingRef, *Thing) bool) {
+		for ancestor := curr.parent; ancestor != nilRef; ancestor = curr.getTreeDataFromThing(ancestor).parent {
+			if !yield(ancestor, curr.things.get(ancestor)) {
+				return
+			}
+		}
+	}
+}
+
+// DepthFirst iterates over all the descendants of this Thing.
+// Parents come before their children.
+// It does not allocate.
+//
+// Changing the tree while looping is unsafe.
+func (curr *Tree[Thing]) DepthFirst() iter.Seq2[ThingRef, *Thing] {
+	return func(yield func(ThingRef, *Thing) bool) {
+		current := curr.firstChild
+		for current != nilRef {
+			if !yield(current, curr.things.get(current)) {
+				return
+			}
+			node := curr.getTreeDataFromThing(current)
+			if node.firstChild != nilRef {
+				current = node.firstChild
+				continue
+			}
+			// go to the next sibling, climbing up until there is one.
+			for {
+				if current == curr.self || node.parent == nilRef {
+					return
+				}
+				parent := curr.getTreeDataFromThing(node.parent)
+				if node.next != parent.firstChild {
+					current = node.next
+					break
+				}
+				current = node.parent
+				node = parent
+			}
+		}
+	}
+}
+
+// BreadthFirst iterates over all the descendants of this Thing, level by level.
+//
+// Changing the tree while looping is unsafe.
+func (curr *Tree[Thing]) BreadthFirst() iter.Seq2[ThingRef, *Thing] {
+	return func(yield func(ThingRef, *Thing) bool) {
+		if curr.firstChild == nilRef {
+			return
+		}
+		// Get queue from Pool.
+		queue, _ := curr.things.refPool.Get().([]ThingRef)
+		defer func() { curr.things.refPool.Put(queue[:0]) }()
+
+		queue = append(queue[:0], curr.self)
+		for i := 0; i < len(queue); i++ {
+			for child, thing := range curr.getTreeDataFromThing(queue[i]).Children() {
+				if !yield(child, thing) {
+					return
+				}
+				queue = append(queue, child)
+			}
+		}
+	}
+}
+
+func (curr *Tree[Thing]) init(selfRef ThingRef, things *Things[Thing], offset uintptr) {
+	curr.self = selfRef
+	curr.things = things
+	curr.offset = offset
+	// must be detached before Thing is deleted.
+	things.treeOffsets = registerOffset(things.treeOffsets, offset)
+}
+
+// detach removes this Thing from the children of its parent.
+func (curr *Tree[Thing]) detach() {
+	if curr.parent == nilRef {
+		return
+	}
+	parent := curr.getTreeDataFromThing(curr.parent)
+	if curr.next == curr.self { // only child
+		parent.firstChild = nilRef
+	} else {
+		curr.getTreeDataFromThing(curr.prev).next = curr.next
+		curr.getTreeDataFromThing(curr.next).prev = curr.prev
+		if parent.firstChild == curr.self {
+			parent.firstChild = curr.next
+		}
+	}
+	curr.parent, curr.next, curr.prev = nilRef, nilRef, nilRef
+}
+
+// orphanChildren turns all children of this Thing into roots.
+func (curr *Tree[Thing]) orphanChildren() {
+	for curr.firstChild != nilRef {
+		curr.getTreeDataFromThing(curr.firstChild).detach()
+	}
+}
+
+// get Tree from this Thing.
+func (curr *Tree[Thing]) getTreeDataFromThing(thingRef ThingRef) *Tree[Thing] {
+	thing := curr.things.get(thingRef)
+	// add the stored offset to the Thing pointer to get pointer to the embedded Tree field
+	return fieldAt[Tree[Thing]](thing, curr.offset)
+}
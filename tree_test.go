@@ -0,0 +1,157 @@
+package ts_test
+
+import (
+	"iter"
+	"slices"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+// Node is a Thing used for testing trees.
+type Node struct {
+	Name     string
+	Children ts.Tree[Node]
+}
+
+// newScene builds:
+//
+//	player
+//	├── weapon
+//	│   ├── scope
+//	│   └── grip
+//	└── shield
+func newScene(t *testing.T) (*ts.Things[Node], map[string]ts.ThingRef) {
+	t.Helper()
+	things := ts.NewThings[Node](16)
+	refs := make(map[string]ts.ThingRef)
+	for _, name := range []string{"player", "weapon", "scope", "grip", "shield"} {
+		refs[name] = things.New(Node{Name: name})
+		things.Get(refs[name]).Children.Init(refs[name], things)
+	}
+	things.Get(refs["weapon"]).Children.SetParent(refs["player"])
+	things.Get(refs["scope"]).Children.SetParent(refs["weapon"])
+	things.Get(refs["grip"]).Children.SetParent(refs["weapon"])
+	things.Get(refs["shield"]).Children.SetParent(refs["player"])
+	return things, refs
+}
+
+func names(seq iter.Seq2[ts.ThingRef, *Node]) []string {
+	var out []string
+	for _, n := range seq {
+		out = append(out, n.Name)
+	}
+	return out
+}
+
+func TestTreeChildrenAndParent(t *testing.T) {
+	things, refs := newScene(t)
+
+	got := names(things.Get(refs["player"]).Children.Children())
+	if want := []string{"weapon", "shield"}; !slices.Equal(got, want) {
+		t.Fatalf("expected children %v, got %v", want, got)
+	}
+	if p := things.Get(refs["scope"]).Children.Parent(); p != refs["weapon"] {
+		t.Fatalf("expected parent of scope to be weapon, got %v", p)
+	}
+	if p := things.Get(refs["player"]).Children.Parent(); p != (ts.ThingRef{}) {
+		t.Fatalf("expected root to have NilRef parent, got %v", p)
+	}
+	got = names(things.Get(refs["grip"]).Children.Ancestors())
+	if want := []string{"weapon", "player"}; !slices.Equal(got, want) {
+		t.Fatalf("expected ancestors %v, got %v", want, got)
+	}
+}
+
+func TestTreeTraversalOrder(t *testing.T) {
+	things, refs := newScene(t)
+	player := &things.Get(refs["player"]).Children
+
+	got := names(player.DepthFirst())
+	if want := []string{"weapon", "scope", "grip", "shield"}; !slices.Equal(got, want) {
+		t.Fatalf("expected depth-first %v, got %v", want, got)
+	}
+	got = names(player.BreadthFirst())
+	if want := []string{"weapon", "shield", "scope", "grip"}; !slices.Equal(got, want) {
+		t.Fatalf("expected breadth-first %v, got %v", want, got)
+	}
+	// traversal of a subtree stays inside the subtree
+	got = names(things.Get(refs["weapon"]).Children.DepthFirst())
+	if want := []string{"scope", "grip"}; !slices.Equal(got, want) {
+		t.Fatalf("expected depth-first of weapon %v, got %v", want, got)
+	}
+}
+
+func TestTreeSetParentMovesAndRejectsCycles(t *testing.T) {
+	things, refs := newScene(t)
+
+	// cycle: player under its own grandchild
+	things.Get(refs["player"]).Children.SetParent(refs["scope"])
+	if p := things.Get(refs["player"]).Children.Parent(); p != (ts.ThingRef{}) {
+		t.Fatalf("expected cycle to be rejected, player parent is %v", p)
+	}
+
+	// move scope from weapon to shield
+	things.Get(refs["scope"]).Children.SetParent(refs["shield"])
+	got := names(things.Get(refs["weapon"]).Children.Children())
+	if want := []string{"grip"}; !slices.Equal(got, want) {
+		t.Fatalf("expected weapon children %v, got %v", want, got)
+	}
+	got = names(things.Get(refs["shield"]).Children.Children())
+	if want := []string{"scope"}; !slices.Equal(got, want) {
+		t.Fatalf("expected shield children %v, got %v", want, got)
+	}
+
+	// detach
+	things.Get(refs["scope"]).Children.SetParent(ts.ThingRef{})
+	if n := len(names(things.Get(refs["shield"]).Children.Children())); n != 0 {
+		t.Fatalf("expected shield to have no children, got %d", n)
+	}
+}
+
+func TestTreeDeleteOrphansChildren(t *testing.T) {
+	things, refs := newScene(t)
+	things.Delete(refs["weapon"])
+
+	got := names(things.Get(refs["player"]).Children.Children())
+	if want := []string{"shield"}; !slices.Equal(got, want) {
+		t.Fatalf("expected player children %v, got %v", want, got)
+	}
+	for _, name := range []string{"scope", "grip"} {
+		if !things.IsNotNil(refs[name]) {
+			t.Fatalf("expected %v to survive", name)
+		}
+		if p := things.Get(refs[name]).Children.Parent(); p != (ts.ThingRef{}) {
+			t.Fatalf("expected %v to become a root, parent is %v", name, p)
+		}
+	}
+}
+
+func TestTreeDeleteRecursive(t *testing.T) {
+	things, refs := newScene(t)
+	things.DeleteRecursive(refs["weapon"])
+
+	for _, name := range []string{"weapon", "scope", "grip"} {
+		if things.IsNotNil(refs[name]) {
+			t.Fatalf("expected %v to be deleted", name)
+		}
+	}
+	got := names(things.Get(refs["player"]).Children.DepthFirst())
+	if want := []string{"shield"}; !slices.Equal(got, want) {
+		t.Fatalf("expected remaining tree %v, got %v", want, got)
+	}
+
+	// reused slots don't inherit the old links
+	reused := things.New(Node{Name: "new"})
+	things.Get(reused).Children.Init(reused, things)
+	if n := len(names(things.Get(reused).Children.Children())); n != 0 {
+		t.Fatalf("expected reused Thing to have no children, got %d", n)
+	}
+
+	things.DeleteRecursive(refs["player"])
+	for ref := range things.Each() {
+		if ref != reused {
+			t.Fatalf("expected only the new Thing to be alive, found %v", ref)
+		}
+	}
+}
@@ -0,0 +1,497 @@
+// Package tstest drives random sequences of operations against a Things pool
+// and compares the results with a simple reference model made of maps and slices.
+//
+// It is meant to be used with Go native fuzzing:
+//
+//	func FuzzInventory(f *testing.F) {
+//		for _, seed := range tstest.Seeds() {
+//			f.Add(seed)
+//		}
+//		h := tstest.Harness[Thing]{
+//			Capacity: 8,
+//			List:     func(t *Thing) *ts.List[Thing] { return &t.Inventory },
+//		}
+//		f.Fuzz(h.Fuzz)
+//	}
+package tstest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+)
+
+// OpKind is the kind of operation performed on the pool.
+type OpKind uint8
+
+const (
+	OpNew OpKind = iota
+	OpDelete
+	OpInit
+	OpAppend
+	OpInsertNext
+	OpPopSelf
+	OpPrepend
+	OpInsertBefore
+	OpMoveToFront
+	OpMoveToBack
+	OpMoveAfter
+	OpSwap
+	OpReverse
+	OpClear
+	OpSplice
+	OpContains
+
+	numOps
+)
+
+func (kind OpKind) String() string {
+	switch kind {
+	case OpNew:
+		return "New"
+	case OpDelete:
+		return "Delete"
+	case OpInit:
+		return "Init"
+	case OpAppend:
+		return "Append"
+	case OpInsertNext:
+		return "InsertNext"
+	case OpPopSelf:
+		return "PopSelf"
+	case OpPrepend:
+		return "Prepend"
+	case OpInsertBefore:
+		return "InsertBefore"
+	case OpMoveToFront:
+		return "MoveToFront"
+	case OpMoveToBack:
+		return "MoveToBack"
+	case OpMoveAfter:
+		return "MoveAfter"
+	case OpSwap:
+		return "Swap"
+	case OpReverse:
+		return "Reverse"
+	case OpClear:
+		return "Clear"
+	case OpSplice:
+		return "Splice"
+	case OpContains:
+		return "Contains"
+	}
+	return fmt.Sprintf("OpKind(%d)", uint8(kind))
+}
+
+// Op is a single operation.
+// A and B pick a ref out of every ref created so far (including deleted ones).
+// The last pick is always the nil ref.
+type Op struct {
+	Kind OpKind
+	A, B uint8
+}
+
+// Decode turns fuzzer input into operations. Every 3 bytes is one Op.
+func Decode(data []byte) []Op {
+	ops := make([]Op, 0, len(data)/3)
+	for ; len(data) >= 3; data = data[3:] {
+		ops = append(ops, Op{Kind: OpKind(data[0] % uint8(numOps)), A: data[1], B: data[2]})
+	}
+	return ops
+}
+
+// Encode is the inverse of Decode.
+func Encode(ops ...Op) []byte {
+	data := make([]byte, 0, len(ops)*3)
+	for _, op := range ops {
+		data = append(data, byte(op.Kind), op.A, op.B)
+	}
+	return data
+}
+
+// Seeds returns operation sequences that cover known tricky cases.
+// Add them to the corpus with f.Add.
+func Seeds() [][]byte {
+	return [][]byte{
+		// append a few Things and pop the first one
+		Encode(Op{OpNew, 0, 0}, Op{OpNew, 0, 0}, Op{OpNew, 0, 0}, Op{OpInit, 0, 0},
+			Op{OpAppend, 0, 1}, Op{OpAppend, 0, 2}, Op{OpPopSelf, 1, 0}),
+		// owner appends itself, then pops itself
+		Encode(Op{OpNew, 0, 0}, Op{OpNew, 0, 0}, Op{OpInit, 0, 0},
+			Op{OpAppend, 0, 0}, Op{OpAppend, 0, 1}, Op{OpPopSelf, 0, 0}, Op{OpAppend, 0, 0}),
+		// insert after the last Thing, then delete a member
+		Encode(Op{OpNew, 0, 0}, Op{OpNew, 0, 0}, Op{OpNew, 0, 0}, Op{OpInit, 0, 0},
+			Op{OpAppend, 0, 1}, Op{OpInsertNext, 1, 2}, Op{OpDelete, 1, 0}),
+		// append the same Thing twice, and into two lists
+		Encode(Op{OpNew, 0, 0}, Op{OpNew, 0, 0}, Op{OpNew, 0, 0}, Op{OpInit, 0, 0}, Op{OpInit, 1, 0},
+			Op{OpAppend, 0, 2}, Op{OpAppend, 0, 2}, Op{OpAppend, 1, 2}, Op{OpAppend, 0, 1}),
+		// delete an owner that is also a member of its own list, then a member that owns a list
+		Encode(Op{OpNew, 0, 0}, Op{OpNew, 0, 0}, Op{OpNew, 0, 0}, Op{OpInit, 0, 0}, Op{OpInit, 1, 0},
+			Op{OpAppend, 0, 1}, Op{OpAppend, 0, 0}, Op{OpAppend, 1, 2}, Op{OpDelete, 0, 0}, Op{OpDelete, 1, 0}),
+		// move, swap and reverse a list that contains its owner, then splice it into another
+		Encode(Op{OpNew, 0, 0}, Op{OpNew, 0, 0}, Op{OpNew, 0, 0}, Op{OpNew, 0, 0}, Op{OpInit, 0, 0}, Op{OpInit, 3, 0},
+			Op{OpAppend, 0, 1}, Op{OpPrepend, 0, 0}, Op{OpInsertBefore, 0, 2}, Op{OpMoveToBack, 2, 0},
+			Op{OpSwap, 0, 1}, Op{OpMoveAfter, 2, 0}, Op{OpMoveToFront, 1, 0}, Op{OpReverse, 0, 0},
+			Op{OpContains, 0, 2}, Op{OpSplice, 0, 3}, Op{OpContains, 3, 2}, Op{OpClear, 3, 0}),
+		// stale refs after a slot is reused
+		Encode(Op{OpNew, 0, 0}, Op{OpInit, 0, 0}, Op{OpDelete, 0, 0}, Op{OpNew, 0, 0},
+			Op{OpAppend, 0, 1}, Op{OpInit, 1, 0}, Op{OpAppend, 1, 0}),
+	}
+}
+
+// Harness runs operations against a Things pool and a reference model.
+type Harness[Thing any] struct {
+	// Capacity is the number of Things the pool is created with.
+	Capacity uint
+	// List returns the List field under test.
+	List func(t *Thing) *ts.List[Thing]
+	// Policy is passed to every List.Init.
+	Policy ts.OwnerPolicy
+}
+
+// Failure describes a mismatch between the pool and the model.
+type Failure struct {
+	Ops  []Op // operations that were run.
+	Step int  // index of the operation after which the mismatch was found.
+	Msg  string
+}
+
+func (f *Failure) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "model mismatch after op %d: %s\n", f.Step, f.Msg)
+	for i, op := range f.Ops {
+		marker := " "
+		if i == f.Step {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "%s %3d: %v(%d, %d)\n", marker, i, op.Kind, op.A, op.B)
+	}
+	return b.String()
+}
+
+// Fuzz decodes data and checks it. Pass it to f.Fuzz.
+func (h Harness[Thing]) Fuzz(t *testing.T, data []byte) {
+	h.Check(t, Decode(data))
+}
+
+// Check runs ops and fails t with the minimal failing operation sequence.
+func (h Harness[Thing]) Check(t testing.TB, ops []Op) {
+	t.Helper()
+	if failure := h.Run(ops); failure != nil {
+		t.Fatal(h.Minimize(failure).Error())
+	}
+}
+
+// Minimize removes operations from a failing sequence for as long as it keeps failing.
+func (h Harness[Thing]) Minimize(failure *Failure) *Failure {
+	// drop everything after the failing op, it can't matter.
+	ops := failure.Ops[:failure.Step+1]
+	for chunk := len(ops) / 2; chunk >= 1; chunk /= 2 {
+		for start := 0; start+chunk <= len(ops); {
+			candidate := append(append([]Op{}, ops[:start]...), ops[start+chunk:]...)
+			if f := h.Run(candidate); f != nil {
+				failure = f
+				ops = f.Ops[:f.Step+1]
+				continue
+			}
+			start++
+		}
+	}
+	failure.Ops = ops
+	return failure
+}
+
+// Run runs ops and returns the first mismatch with the model, or nil.
+func (h Harness[Thing]) Run(ops []Op) (failure *Failure) {
+	r := newRunner(h)
+	step := 0
+	defer func() {
+		if err := recover(); err != nil {
+			failure = &Failure{Ops: ops, Step: step, Msg: fmt.Sprint("panic: ", err)}
+		}
+	}()
+	for ; step < len(ops); step++ {
+		r.apply(ops[step])
+		if msg := r.verify(); msg != "" {
+			return &Failure{Ops: ops, Step: step, Msg: msg}
+		}
+	}
+	return nil
+}
+
+type runner[Thing any] struct {
+	h      Harness[Thing]
+	things *ts.Things[Thing]
+
+	// every ref ever returned by New
+	refs []ts.ThingRef
+
+	// model
+	alive    map[ts.ThingRef]bool
+	lists    map[ts.ThingRef][]ts.ThingRef // initialized owners -> members in order
+	memberOf map[ts.ThingRef]ts.ThingRef   // member -> owner
+	live     uint
+	msg      string // mismatch found while applying an op
+}
+
+func newRunner[Thing any](h Harness[Thing]) *runner[Thing] {
+	return &runner[Thing]{
+		h:        h,
+		things:   ts.NewThings[Thing](h.Capacity),
+		alive:    make(map[ts.ThingRef]bool),
+		lists:    make(map[ts.ThingRef][]ts.ThingRef),
+		memberOf: make(map[ts.ThingRef]ts.ThingRef),
+	}
+}
+
+func (r *runner[Thing]) pick(b uint8) ts.ThingRef {
+	i := int(b) % (len(r.refs) + 1)
+	if i == len(r.refs) {
+		return ts.ThingRef{}
+	}
+	return r.refs[i]
+}
+
+func (r *runner[Thing]) list(ref ts.ThingRef) *ts.List[Thing] {
+	return r.h.List(r.things.Get(ref))
+}
+
+func (r *runner[Thing]) apply(op Op) {
+	a, b := r.pick(op.A), r.pick(op.B)
+	switch op.Kind {
+	case OpNew:
+		var zero Thing
+		ref := r.things.New(zero)
+		if r.live == r.h.Capacity {
+			if ref != (ts.ThingRef{}) {
+				r.msg = fmt.Sprintf("New on a full pool returned %v", ref)
+			}
+			return
+		}
+		if ref == (ts.ThingRef{}) || r.alive[ref] {
+			r.msg = fmt.Sprintf("New returned %v", ref)
+			return
+		}
+		r.refs = append(r.refs, ref)
+		r.alive[ref] = true
+		r.live++
+	case OpDelete:
+		r.things.Delete(a)
+		if r.alive[a] {
+			r.delete(a)
+		}
+	case OpInit:
+		r.list(a).Init(a, r.things, r.h.Policy)
+		_, isOwner := r.lists[a]
+		_, isMember := r.memberOf[a]
+		if r.alive[a] && !isOwner && !isMember {
+			r.lists[a] = []ts.ThingRef{}
+		}
+	case OpAppend:
+		r.list(a).Append(b)
+		if _, ok := r.lists[a]; ok && r.canLink(a, b) {
+			r.lists[a] = append(r.lists[a], b)
+			r.memberOf[b] = a
+		}
+	case OpInsertNext:
+		r.list(a).InsertNext(b)
+		owner, ok := r.memberOf[a]
+		if ok && r.alive[a] && r.canLink(owner, b) {
+			members := r.lists[owner]
+			i := slices.Index(members, a) + 1
+			r.lists[owner] = slices.Insert(members, i, b)
+			r.memberOf[b] = owner
+		}
+	case OpPopSelf:
+		r.list(a).PopSelf()
+		r.unlink(a)
+	case OpPrepend:
+		r.list(a).Prepend(b)
+		if _, ok := r.lists[a]; ok && r.canLink(a, b) {
+			r.lists[a] = slices.Insert(r.lists[a], 0, b)
+			r.memberOf[b] = a
+		}
+	case OpInsertBefore:
+		r.list(a).InsertBefore(b)
+		owner, ok := r.memberOf[a]
+		if ok && r.canLink(owner, b) {
+			members := r.lists[owner]
+			r.lists[owner] = slices.Insert(members, slices.Index(members, a), b)
+			r.memberOf[b] = owner
+		}
+	case OpMoveToFront:
+		r.list(a).MoveToFront()
+		if owner, ok := r.memberOf[a]; ok {
+			r.unlink(a)
+			r.lists[owner] = slices.Insert(r.lists[owner], 0, a)
+			r.memberOf[a] = owner
+		}
+	case OpMoveToBack:
+		r.list(a).MoveToBack()
+		if owner, ok := r.memberOf[a]; ok {
+			r.unlink(a)
+			r.lists[owner] = append(r.lists[owner], a)
+			r.memberOf[a] = owner
+		}
+	case OpMoveAfter:
+		r.list(a).MoveAfter(b)
+		if owner, ok := r.memberOf[a]; ok && a != b && r.memberOf[b] == owner && r.alive[b] {
+			r.unlink(a)
+			members := r.lists[owner]
+			r.lists[owner] = slices.Insert(members, slices.Index(members, b)+1, a)
+			r.memberOf[a] = owner
+		}
+	case OpSwap:
+		r.list(a).Swap(b)
+		if owner, ok := r.memberOf[a]; ok && r.memberOf[b] == owner && r.alive[b] {
+			members := r.lists[owner]
+			i, j := slices.Index(members, a), slices.Index(members, b)
+			members[i], members[j] = members[j], members[i]
+		}
+	case OpReverse:
+		r.list(a).Reverse()
+		slices.Reverse(r.lists[a])
+	case OpClear:
+		r.list(a).Clear()
+		for _, member := range r.lists[a] {
+			delete(r.memberOf, member)
+		}
+		if _, ok := r.lists[a]; ok {
+			r.lists[a] = []ts.ThingRef{}
+		}
+	case OpSplice:
+		r.list(a).Splice(r.list(b))
+		_, okA := r.lists[a]
+		_, okB := r.lists[b]
+		if !okA || !okB || a == b {
+			return
+		}
+		r.unlink(a) // the owner stays behind
+		for _, member := range r.lists[a] {
+			r.memberOf[member] = b
+		}
+		r.lists[b] = append(r.lists[b], r.lists[a]...)
+		r.lists[a] = []ts.ThingRef{}
+	case OpContains:
+		got := r.list(a).Contains(b)
+		owner, ok := r.memberOf[a]
+		if _, isOwner := r.lists[a]; isOwner {
+			owner, ok = a, true
+		}
+		if want := ok && r.alive[b] && r.memberOf[b] == owner; got != want {
+			r.msg = fmt.Sprintf("Contains(%v) on %v = %v, want %v", b, a, got, want)
+		}
+	}
+}
+
+// canLink reports whether the model allows ref to be linked into the list of owner.
+func (r *runner[Thing]) canLink(owner, ref ts.ThingRef) bool {
+	_, isMember := r.memberOf[ref]
+	_, isOwner := r.lists[ref]
+	return r.alive[ref] && !isMember && (!isOwner || ref == owner)
+}
+
+// unlink removes ref from the list it is inside of.
+func (r *runner[Thing]) unlink(ref ts.ThingRef) {
+	owner, ok := r.memberOf[ref]
+	if !ok {
+		return
+	}
+	members := r.lists[owner]
+	i := slices.Index(members, ref)
+	r.lists[owner] = append(members[:i], members[i+1:]...)
+	delete(r.memberOf, ref)
+}
+
+// delete removes ref from the model, following the OwnerPolicy for its list.
+func (r *runner[Thing]) delete(ref ts.ThingRef) {
+	members := slices.Clone(r.lists[ref])
+	if r.h.Policy == ts.Forbid && slices.ContainsFunc(members, func(m ts.ThingRef) bool { return m != ref }) {
+		return
+	}
+	r.unlink(ref)
+	delete(r.lists, ref)
+	r.alive[ref] = false
+	r.live--
+	for _, member := range members {
+		if member == ref {
+			continue
+		}
+		delete(r.memberOf, member)
+		if r.h.Policy == ts.CascadeDelete {
+			r.delete(member)
+		}
+	}
+}
+
+// verify compares the pool with the model.
+func (r *runner[Thing]) verify() string {
+	if r.msg != "" {
+		return r.msg
+	}
+	for _, ref := range r.refs {
+		if r.things.IsNotNil(ref) != r.alive[ref] {
+			return fmt.Sprintf("IsNotNil(%v) = %v, want %v", ref, !r.alive[ref], r.alive[ref])
+		}
+	}
+	var live uint
+	for ref := range r.things.Each() {
+		if !r.alive[ref] {
+			return fmt.Sprintf("Things.Each yielded dead %v", ref)
+		}
+		live++
+	}
+	if live != r.live {
+		return fmt.Sprintf("Things.Each yielded %d Things, want %d", live, r.live)
+	}
+
+	for owner, members := range r.lists {
+		list := r.list(owner)
+		var got []ts.ThingRef
+		for ref := range list.Each() {
+			got = append(got, ref)
+			if len(got) > len(members) {
+				break // the ring is broken, don't loop forever.
+			}
+		}
+		if !slices.Equal(got, members) {
+			return fmt.Sprintf("list of %v is %v, want %v", owner, got, members)
+		}
+		if count := list.Count(); count != len(members) {
+			return fmt.Sprintf("Count of %v is %d, want %d", owner, count, len(members))
+		}
+		if n := list.Len(); n != len(members) {
+			return fmt.Sprintf("Len of %v is %d, want %d", owner, n, len(members))
+		}
+		if len(members) == 0 {
+			continue
+		}
+		if list.First() != r.things.Get(members[0]) {
+			return fmt.Sprintf("First of %v is not %v", owner, members[0])
+		}
+		if list.Last() != r.things.Get(members[len(members)-1]) {
+			return fmt.Sprintf("Last of %v is not %v", owner, members[len(members)-1])
+		}
+		for i, member := range members {
+			node := r.list(member)
+			if node.Owner() != owner {
+				return fmt.Sprintf("Owner of %v is %v, want %v", member, node.Owner(), owner)
+			}
+			if n := node.Len(); n != len(members) {
+				return fmt.Sprintf("Len through member %v is %d, want %d", member, n, len(members))
+			}
+			next := members[(i+1)%len(members)]
+			prev := members[(i+len(members)-1)%len(members)]
+			if node.Next() != r.things.Get(next) {
+				return fmt.Sprintf("Next of %v is not %v", member, next)
+			}
+			if node.Prev() != r.things.Get(prev) {
+				return fmt.Sprintf("Prev of %v is not %v", member, prev)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package tstest_test
+
+import (
+	"slices"
+	"testing"
+
+	ts "github.com/BrownNPC/thing-system"
+	"github.com/BrownNPC/thing-system/tstest"
+)
+
+func init() {
+	ts.SetLogger(nil)
+}
+
+type thing struct {
+	Value int
+	Items ts.List[thing]
+}
+
+func TestDecodeEncode(t *testing.T) {
+	ops := []tstest.Op{{tstest.OpNew, 1, 2}, {tstest.OpAppend, 3, 4}, {tstest.OpPopSelf, 5, 6}}
+	got := tstest.Decode(tstest.Encode(ops...))
+	if !slices.Equal(got, ops) {
+		t.Fatalf("expected %v, got %v", ops, got)
+	}
+	// trailing bytes that don't make a full Op are ignored
+	if n := len(tstest.Decode([]byte{0, 0, 0, 1})); n != 1 {
+		t.Fatalf("expected 1 op, got %d", n)
+	}
+}
+
+func TestHarnessPassesSeeds(t *testing.T) {
+	h := tstest.Harness[thing]{
+		Capacity: 4,
+		List:     func(t *thing) *ts.List[thing] { return &t.Items },
+	}
+	for _, seed := range tstest.Seeds() {
+		h.Check(t, tstest.Decode(seed))
+	}
+}
+
+func TestHarnessReportsMinimalSequence(t *testing.T) {
+	// returns a List that is not part of the Thing, so nothing ever gets linked.
+	h := tstest.Harness[thing]{
+		Capacity: 4,
+		List:     func(t *thing) *ts.List[thing] { return new(ts.List[thing]) },
+	}
+	ops := tstest.Decode(slices.Concat(tstest.Seeds()...))
+	failure := h.Run(ops)
+	if failure == nil {
+		t.Fatal("expected the broken List accessor to be detected")
+	}
+	minimal := h.Minimize(failure)
+	if len(minimal.Ops) > 4 || minimal.Ops[len(minimal.Ops)-1].Kind != tstest.OpAppend {
+		t.Fatalf("expected a short sequence ending in Append, got:\n%v", minimal)
+	}
+	if f := h.Run(minimal.Ops); f == nil {
+		t.Fatal("minimal sequence does not fail")
+	}
+}
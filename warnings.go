@@ -0,0 +1,50 @@
+package ts
+
+import (
+	"maps"
+	"sync"
+)
+
+// warningCounts counts the misuse warnings and errors, by message.
+// They are counted for all the pools together, because Lists and Trees can report misuse without a pool.
+var warningCounts struct {
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+// WarningCounts returns how many times each misuse warning or error happened, by message,
+// like "Derefence of NilRef." or "Tried to Append to uninitialized list".
+//
+// The counts are global, for all the pools together.
+// They are counted even if the logger is nil, but not in release builds.
+// It is safe to call from any goroutine.
+func WarningCounts() map[string]uint64 {
+	warningCounts.mu.Lock()
+	defer warningCounts.mu.Unlock()
+	return maps.Clone(warningCounts.counts)
+}
+
+func countWarning(message string) {
+	warningCounts.mu.Lock()
+	defer warningCounts.mu.Unlock()
+	if warningCounts.counts == nil {
+		warningCounts.counts = make(map[string]uint64)
+	}
+	warningCounts.counts[message]++
+}
+
+// logWarn counts the warning, and logs it with the caller skip frames above the function that calls logWarn.
+func logWarn(skip int, message string, args ...any) {
+	countWarning(message)
+	if logger != nil {
+		logger.Warn(message, append(args, "file", getParentCaller(1+skip))...)
+	}
+}
+
+// logError is the same as logWarn, at the error level.
+func logError(skip int, message string, args ...any) {
+	countWarning(message)
+	if logger != nil {
+		logger.Error(message, append(args, "file", getParentCaller(1+skip))...)
+	}
+}